@@ -2,6 +2,7 @@ package banner
 
 import (
 	"fmt"
+	"os"
 )
 
 // prints the version message
@@ -11,7 +12,7 @@ func PrintVersion() {
 	fmt.Printf("Current linkinspector version %s\n", version)
 }
 
-// Prints the Colorful banner
+// Prints the Colorful banner to stderr, keeping stdout for the results
 func PrintBanner() {
 	banner := `
     __ _         __    _                                  __              
@@ -21,5 +22,5 @@ func PrintBanner() {
 /_//_//_/ /_//_/|_|/_//_/ /_//____// .___/ \___/ \___/ \__/ \____//_/     
                                   /_/
 `
-	fmt.Printf("%s\n%75s\n\n", banner, "Current linkinspector version "+version)
+	fmt.Fprintf(os.Stderr, "%s\n%75s\n\n", banner, "Current linkinspector version "+version)
 }
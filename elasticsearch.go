@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+const (
+	esBatchSize     = 500             // Documents per bulk request.
+	esFlushInterval = 5 * time.Second // Max time a document waits to be indexed.
+	esRetries       = 3               // Retries of bulk requests answered with 429 or 5xx.
+)
+
+// A result indexed in Elasticsearch, with the scan it belongs to.
+type esDocument struct {
+	*inspector.Result
+	ScanID    string    `json:"scan_id"`
+	Timestamp time.Time `json:"@timestamp"`
+}
+
+// Bulk indexes the results into an Elasticsearch or OpenSearch index in the
+// background.
+type esSink struct {
+	bulkURL  string
+	index    string
+	username string
+	password string
+	scanID   string
+	docs     chan esDocument
+	done     chan struct{}
+}
+
+// Create the sink of an index URL such as http://host:9200/index, the
+// credentials of the URL being sent with basic authentication.
+func newESSink(rawURL string) (*esSink, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Elasticsearch URL %s, use http(s)://host:9200/index", rawURL)
+	}
+	base, index := "", strings.Trim(parsed.Path, "/")
+	if i := strings.LastIndex(index, "/"); i >= 0 {
+		base, index = "/"+index[:i], index[i+1:]
+	}
+	if index == "" {
+		return nil, fmt.Errorf("missing index in Elasticsearch URL %s", rawURL)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	s := &esSink{
+		bulkURL: parsed.Scheme + "://" + parsed.Host + base + "/_bulk",
+		index:   index,
+		scanID:  hex.EncodeToString(id),
+		docs:    make(chan esDocument, esBatchSize),
+		done:    make(chan struct{}),
+	}
+	if parsed.User != nil {
+		s.username = parsed.User.Username()
+		s.password, _ = parsed.User.Password()
+	}
+	go s.run()
+	return s, nil
+}
+
+// Queue a result for indexing, failed inspections are not indexed.
+func (s *esSink) add(result *inspector.Result) {
+	if result.Err == nil {
+		s.docs <- esDocument{Result: result, ScanID: s.scanID, Timestamp: time.Now().UTC()}
+	}
+}
+
+// Index the queued results and wait until they are indexed.
+func (s *esSink) close() {
+	close(s.docs)
+	<-s.done
+}
+
+// Index the queued results in batches, when a batch is full or has waited
+// for the flush interval.
+func (s *esSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(esFlushInterval)
+	defer ticker.Stop()
+
+	var batch []esDocument
+	for {
+		select {
+		case doc, ok := <-s.docs:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, doc)
+			if len(batch) >= esBatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// Send a batch with the bulk API, reporting the documents that failed.
+func (s *esSink) flush(batch []esDocument) {
+	if len(batch) == 0 {
+		return
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	action := map[string]map[string]string{"index": {"_index": s.index}}
+	for _, doc := range batch {
+		_ = encoder.Encode(action)
+		_ = encoder.Encode(doc)
+	}
+
+	reply, err := s.bulk(body.Bytes())
+	if err != nil {
+		logger.Error("indexing results in Elasticsearch", "results", len(batch), "error", err)
+		return
+	}
+	var bulkReply struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(reply, &bulkReply); err != nil || !bulkReply.Errors {
+		return
+	}
+	failed := 0
+	var firstErr json.RawMessage
+	for _, item := range bulkReply.Items {
+		for _, result := range item {
+			if result.Error != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = result.Error
+				}
+			}
+		}
+	}
+	logger.Error("indexing results in Elasticsearch", "failed", failed, "results", len(batch), "error", string(firstErr))
+}
+
+// Post a bulk request, retrying when Elasticsearch is overloaded.
+func (s *esSink) bulk(body []byte) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		req, err := http.NewRequestWithContext(ctx, "POST", s.bulkURL, bytes.NewReader(body))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		if s.username != "" {
+			req.SetBasicAuth(s.username, s.password)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		reply, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return reply, nil
+		case (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500) && attempt < esRetries:
+			logger.Debug("retrying Elasticsearch bulk request", "status", resp.StatusCode)
+			time.Sleep(time.Duration(attempt+1) * time.Second)
+		default:
+			return nil, errors.New(resp.Status + ": " + strings.TrimSpace(string(reply)))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Data of the output template, the fields of the JSON data being promoted
+// so they read as {{.StatusCode}} or {{.Suffix}}.
+type templateResult struct {
+	URL       string
+	Type      string
+	Error     string // Error of a failed URL, included with -include-errors.
+	ErrorType string
+	inspector.Data
+}
+
+// Functions available in output templates.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// Parse an output template, such as '{{.URL}} {{.StatusCode}} {{.Suffix}}'.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	// Catch unknown fields before the scan rather than on every result, other
+	// errors such as indexing an empty list may not happen with real results.
+	if err := tmpl.Execute(io.Discard, templateResult{}); err != nil && strings.Contains(err.Error(), "can't evaluate field") {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Format a result with the output template, as a single line unless the
+// template ends with its own newline.
+func formatTemplate(tmpl *template.Template, result *inspector.Result) string {
+	data := templateResult{URL: result.Host, Type: result.Type, Data: result.Data}
+	if result.Err != nil {
+		data.Error = result.Err.Error()
+		data.ErrorType = inspector.ErrorType(result.Err)
+	}
+	var line strings.Builder
+	if err := tmpl.Execute(&line, data); err != nil {
+		logger.Warn("formatting result with the output template", "url", result.Host, "error", err)
+		return ""
+	}
+	if !strings.HasSuffix(line.String(), "\n") {
+		line.WriteString("\n")
+	}
+	return line.String()
+}
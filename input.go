@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic bytes of the supported compression formats.
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	bzip2Magic = []byte("BZh")
+)
+
+// An input stream with the cleanups to run when closing it.
+type inputReader struct {
+	io.Reader
+	closers []func() error
+}
+
+func (r *inputReader) Close() error {
+	var firstErr error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if err := r.closers[i](); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Report whether the input list is fetched over HTTP rather than read from disk.
+func isRemoteInput(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// Open the input list, a local file or an http(s) URL, streaming it through
+// a decompressor when it is gzip, zstd or bzip2 compressed.
+func openInput(ctx context.Context, name string) (io.ReadCloser, error) {
+	input := &inputReader{}
+	if isRemoteInput(name) {
+		req, err := http.NewRequestWithContext(ctx, "GET", name, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		input.Reader = resp.Body
+		input.closers = append(input.closers, resp.Body.Close)
+	} else {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		input.Reader = file
+		input.closers = append(input.closers, file.Close)
+	}
+
+	// Detect the compression from the magic bytes rather than the extension.
+	buffered := bufio.NewReader(input.Reader)
+	magic, _ := buffered.Peek(4)
+	input.Reader = buffered
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		decompressor, err := gzip.NewReader(buffered)
+		if err != nil {
+			input.Close()
+			return nil, fmt.Errorf("reading gzip input: %w", err)
+		}
+		input.Reader = decompressor
+		input.closers = append(input.closers, decompressor.Close)
+	case bytes.HasPrefix(magic, zstdMagic):
+		decompressor, err := zstd.NewReader(buffered)
+		if err != nil {
+			input.Close()
+			return nil, fmt.Errorf("reading zstd input: %w", err)
+		}
+		input.Reader = decompressor
+		input.closers = append(input.closers, func() error { decompressor.Close(); return nil })
+	case bytes.HasPrefix(magic, bzip2Magic):
+		input.Reader = bzip2.NewReader(buffered)
+	}
+	return input, nil
+}
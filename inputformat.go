@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Formats of the input list.
+var inputFormats = []string{"lines", "burp", "har", "sitemap", "nmap", "jsonl"}
+
+// Validate an input format, the empty format reading one URL per line.
+func validateInputFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, known := range inputFormats {
+		if format == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid input format %q, use %s", format, strings.Join(inputFormats, ", "))
+}
+
+// Extract the URLs of the input in the given format, calling emit for each
+// one until it returns false. Lines of more than maxLength bytes, unlimited
+// when zero, are skipped and their number passed to overlong when not nil.
+// Only the jsonl format sets more than the URL of the requests.
+func extractURLs(format string, input io.Reader, maxLength int, emit func(inspector.Request) bool, overlong func(line int)) error {
+	emitURL := func(url string) bool {
+		return emit(inspector.Request{URL: url})
+	}
+	switch format {
+	case "burp":
+		return extractXMLElements(input, "url", emitURL)
+	case "sitemap":
+		return extractXMLElements(input, "loc", emitURL)
+	case "har":
+		return extractHAR(input, emitURL)
+	case "nmap":
+		return extractNmap(input, emitURL)
+	case "jsonl":
+		return extractLines(input, maxLength, func(line string) bool {
+			request, err := parseRequestLine(line)
+			if err != nil {
+				logger.Warn("skipping invalid input request", "error", err)
+				return true
+			}
+			return emit(request)
+		}, overlong)
+	}
+
+	return extractLines(input, maxLength, emitURL, overlong)
+}
+
+// A request of the jsonl input format.
+type requestLine struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    *string           `json:"body"`
+}
+
+// Parse a line of the jsonl input format.
+func parseRequestLine(line string) (inspector.Request, error) {
+	var parsed requestLine
+	if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+		return inspector.Request{}, err
+	}
+	request := inspector.Request{URL: strings.TrimSpace(parsed.URL), Method: parsed.Method}
+	if request.URL == "" {
+		return inspector.Request{}, fmt.Errorf("missing url")
+	}
+	if len(parsed.Headers) > 0 {
+		request.Headers = make(http.Header, len(parsed.Headers))
+		for name, value := range parsed.Headers {
+			request.Headers.Set(name, value)
+		}
+	}
+	if parsed.Body != nil {
+		request.Body = []byte(*parsed.Body)
+	}
+	return request, nil
+}
+
+// Extract the URL of every line, reading the lines in chunks so that long
+// URLs such as data URIs or signed links don't stop the input.
+func extractLines(input io.Reader, maxLength int, emit func(string) bool, overlong func(line int)) error {
+	reader := bufio.NewReaderSize(input, 64*1024)
+	var line []byte
+	number, read := 0, 0 // Number of the current line, bytes read of it.
+	skipped := false     // The current line is too long, its next chunks are dropped.
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if err != nil && err != bufio.ErrBufferFull && err != io.EOF {
+			return err
+		}
+		read += len(chunk)
+		if !skipped {
+			line = append(line, chunk...)
+			// Surrounding whitespace doesn't count, trailing whitespace being
+			// bounded by the line end.
+			if maxLength > 0 && len(bytes.TrimLeft(line, " \t")) > maxLength+2 {
+				skipped = true
+				line = line[:0]
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		if read == 0 {
+			return nil // Nothing after the last line end.
+		}
+		number++
+		url := strings.TrimSpace(string(line))
+		if skipped || (maxLength > 0 && len(url) > maxLength) {
+			if overlong != nil {
+				overlong(number)
+			}
+		} else if url != "" && !emit(url) {
+			return nil
+		}
+		if err == io.EOF {
+			return nil
+		}
+		line = line[:0]
+		read = 0
+		skipped = false
+	}
+}
+
+// Stream the text of every XML element with the given local name: <url> of
+// the Burp items exports, <loc> of sitemaps and sitemap indexes.
+func extractXMLElements(input io.Reader, name string, emit func(string) bool) error {
+	decoder := xml.NewDecoder(input)
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != name {
+			continue
+		}
+		var text string
+		if err := decoder.DecodeElement(&text, &start); err != nil {
+			return err
+		}
+		if text = strings.TrimSpace(text); text != "" && !emit(text) {
+			return nil
+		}
+	}
+}
+
+// Extract the request URLs of a HAR file.
+func extractHAR(input io.Reader, emit func(string) bool) error {
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					URL string `json:"url"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.NewDecoder(input).Decode(&har); err != nil {
+		return fmt.Errorf("parsing HAR: %w", err)
+	}
+	for _, entry := range har.Log.Entries {
+		if entry.Request.URL != "" && !emit(entry.Request.URL) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Ports treated as web services even when nmap didn't identify the service.
+var webPorts = map[int]string{80: "http", 8000: "http", 8080: "http", 443: "https", 8443: "https"}
+
+// Build the http(s) URLs of the open web ports of an nmap XML report. The
+// scheme comes from the service name and its SSL tunnel, hosts being named
+// by their hostname when nmap knows one.
+func extractNmap(input io.Reader, emit func(string) bool) error {
+	var report struct {
+		Hosts []struct {
+			Addresses []struct {
+				Addr string `xml:"addr,attr"`
+				Type string `xml:"addrtype,attr"`
+			} `xml:"address"`
+			Hostnames []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"hostnames>hostname"`
+			Ports []struct {
+				ID    int `xml:"portid,attr"`
+				State struct {
+					State string `xml:"state,attr"`
+				} `xml:"state"`
+				Service struct {
+					Name   string `xml:"name,attr"`
+					Tunnel string `xml:"tunnel,attr"`
+				} `xml:"service"`
+			} `xml:"ports>port"`
+		} `xml:"host"`
+	}
+	if err := xml.NewDecoder(input).Decode(&report); err != nil {
+		return fmt.Errorf("parsing nmap XML: %w", err)
+	}
+
+	for _, host := range report.Hosts {
+		var name string
+		for _, address := range host.Addresses {
+			if address.Type == "ipv4" || address.Type == "ipv6" {
+				name = address.Addr
+				break
+			}
+		}
+		if len(host.Hostnames) > 0 && host.Hostnames[0].Name != "" {
+			name = host.Hostnames[0].Name
+		}
+		if name == "" {
+			continue
+		}
+
+		for _, port := range host.Ports {
+			if port.State.State != "open" {
+				continue
+			}
+			service := strings.ToLower(port.Service.Name)
+			scheme := webPorts[port.ID]
+			switch {
+			case strings.Contains(service, "https") || (strings.Contains(service, "http") && port.Service.Tunnel == "ssl"):
+				scheme = "https"
+			case strings.Contains(service, "http"):
+				scheme = "http"
+			}
+			if scheme == "" {
+				continue
+			}
+
+			address := net.JoinHostPort(name, strconv.Itoa(port.ID))
+			if (scheme == "http" && port.ID == 80) || (scheme == "https" && port.ID == 443) {
+				address = name
+				if strings.Contains(name, ":") {
+					address = "[" + name + "]"
+				}
+			}
+			if !emit(scheme + "://" + address) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
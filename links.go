@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Writes the links extracted from the delivered HTML pages to a file, each
+// link once, as plain lines to feed the next tool or, with JSON output, as
+// {"url","source"} lines tagged with the page listing them.
+type linksWriter struct {
+	output *outputWriter
+	json   bool
+	seen   map[string]bool
+}
+
+// Create the links file, replacing a previous one.
+func newLinksWriter(fileName string, json bool) (*linksWriter, error) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("opening links file %s: %w", fileName, err)
+	}
+	return &linksWriter{output: newOutputWriter(io.Discard, file), json: json, seen: make(map[string]bool)}, nil
+}
+
+// Write the links of a result not written yet.
+func (w *linksWriter) add(result *inspector.Result) {
+	for _, link := range result.Data.Links {
+		if w.seen[link] {
+			continue
+		}
+		w.seen[link] = true
+		if !w.json {
+			w.output.write(link + "\n")
+			continue
+		}
+		line, err := json.Marshal(struct {
+			URL    string `json:"url"`
+			Source string `json:"source"`
+		}{link, result.Host})
+		if err == nil {
+			w.output.write(string(line) + "\n")
+		}
+	}
+}
+
+// Flush the links and close the file.
+func (w *linksWriter) close() {
+	w.output.close()
+}
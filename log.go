@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logrusorgru/aurora/v4"
+)
+
+// Logger of the diagnostics, always written to stderr so stdout only holds
+// the results.
+var logger = slog.New(newCLIHandler(os.Stderr, slog.LevelInfo, true))
+
+// Set up the logger of the options. The level defaults to info, warn with
+// -silent and debug with -verbose.
+func setupLogger(options *Options) error {
+	var level slog.Level
+	switch strings.ToLower(options.LogLevel) {
+	case "":
+		switch {
+		case options.Verbose:
+			level = slog.LevelDebug
+		case options.Silent:
+			level = slog.LevelWarn
+		default:
+			level = slog.LevelInfo
+		}
+	case "debug":
+		level = slog.LevelDebug
+	case "info":
+		level = slog.LevelInfo
+	case "warn", "warning":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		return fmt.Errorf("invalid log level %s, use debug, info, warn or error", options.LogLevel)
+	}
+
+	if options.LogJSON {
+		logger = slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	} else {
+		logger = slog.New(newCLIHandler(os.Stderr, level, options.NoColor))
+	}
+	return nil
+}
+
+// Handler writing the records as short readable lines, such as
+// "[WRN] fetching URL url=https://example.com error=timeout".
+type cliHandler struct {
+	mu      *sync.Mutex
+	w       io.Writer
+	level   slog.Leveler
+	noColor bool
+	attrs   string // Attributes added with WithAttrs, already formatted.
+	group   string // Prefix of the attribute keys added with WithGroup.
+}
+
+func newCLIHandler(w io.Writer, level slog.Leveler, noColor bool) *cliHandler {
+	return &cliHandler{mu: &sync.Mutex{}, w: w, level: level, noColor: noColor}
+}
+
+func (h *cliHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *cliHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+	line.WriteString(h.levelTag(record.Level))
+	line.WriteString(" ")
+	line.WriteString(record.Message)
+	line.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&line, h.group, attr)
+		return true
+	})
+	line.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String())
+	return err
+}
+
+func (h *cliHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var line strings.Builder
+	for _, attr := range attrs {
+		appendAttr(&line, h.group, attr)
+	}
+	clone := *h
+	clone.attrs += line.String()
+	return &clone
+}
+
+func (h *cliHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group += name + "."
+	return &clone
+}
+
+// Tag of a level, colored unless colors are disabled.
+func (h *cliHandler) levelTag(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		if h.noColor {
+			return "[ERR]"
+		}
+		return aurora.Red("[ERR]").String()
+	case level >= slog.LevelWarn:
+		if h.noColor {
+			return "[WRN]"
+		}
+		return aurora.Yellow("[WRN]").String()
+	case level >= slog.LevelInfo:
+		if h.noColor {
+			return "[INF]"
+		}
+		return aurora.Blue("[INF]").String()
+	default:
+		if h.noColor {
+			return "[DBG]"
+		}
+		return aurora.Magenta("[DBG]").String()
+	}
+}
+
+// Append an attribute as key=value, quoting values with spaces.
+func appendAttr(line *strings.Builder, group string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if value.Kind() == slog.KindGroup {
+		prefix := group
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, groupAttr := range value.Group() {
+			appendAttr(line, prefix, groupAttr)
+		}
+		return
+	}
+
+	var text string
+	switch value.Kind() {
+	case slog.KindDuration:
+		text = value.Duration().Round(time.Millisecond).String()
+	case slog.KindTime:
+		text = value.Time().Format(time.DateTime)
+	default:
+		text = value.String()
+	}
+	if text == "" || strings.ContainsAny(text, " \t\r\n\"=") {
+		text = strconv.Quote(text)
+	}
+	line.WriteString(" " + group + attr.Key + "=" + text)
+}
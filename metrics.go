@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Serve the metrics in the Prometheus text format at /metrics of addr, the
+// listener being opened right away so a busy address fails before scanning.
+func startMetricsServer(addr string, metrics *inspector.Metrics) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := metrics.WritePrometheus(w); err != nil {
+			logger.Debug("writing metrics", "error", err)
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("serving metrics", "addr", addr, "error", err)
+		}
+	}()
+	logger.Info("serving metrics", "url", "http://"+listener.Addr().String()+"/metrics")
+	return server, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Kinds of changes reported in monitor mode.
+const (
+	changeNew     = "new"
+	changeChanged = "changed"
+	changeRemoved = "removed"
+)
+
+// Results of the previous scan, saved between the scans of monitor mode.
+type monitorState struct {
+	Scanned time.Time                    `json:"scanned"`
+	Results map[string]*inspector.Result `json:"results"`
+}
+
+// Scan the input at every interval until interrupted, delivering only the
+// results that are new, changed or removed since the previous scan.
+func monitor(ctx context.Context, runner *inspector.Runner, options *Options, deliver func(*inspector.Result), done func(inspector.Stats)) {
+	state, err := loadMonitorState(options.MonitorState)
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if !state.Scanned.IsZero() {
+		logger.Info("reporting the changes since the previous scan", "scanned", state.Scanned)
+	}
+
+	for {
+		start := time.Now()
+		current := make(map[string]*inspector.Result)
+		runner.SetValidators(monitorValidators(state))
+		stats, err := runScan(ctx, runner, options, func(result *inspector.Result) {
+			// Failed URLs are reported as removed once the scan is over.
+			if result.Err != nil {
+				return
+			}
+			key := monitorKey(result)
+			previous, ok := state.Results[key]
+			// A 304 response confirms the previous result without a body to compare.
+			if result.Data.NotModified && ok {
+				current[key] = previous
+				return
+			}
+			current[key] = result
+			if !ok {
+				result.Data.Change = changeNew
+			} else if changes := diffResults(previous, result); len(changes) > 0 {
+				result.Data.Change = changeChanged
+				result.Data.Changes = changes
+			} else {
+				return
+			}
+			deliver(result)
+		})
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		// An interrupted scan misses results, don't report them as removed.
+		if err != nil || ctx.Err() != nil {
+			return
+		}
+
+		keys := make([]string, 0, len(state.Results))
+		for key := range state.Results {
+			if current[key] == nil {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			removed := state.Results[key]
+			removed.Data.Change = changeRemoved
+			removed.Data.Changes = nil
+			deliver(removed)
+		}
+		done(stats)
+
+		// Keep the results without their bodies, which aren't compared.
+		for _, result := range current {
+			result.Body = nil
+			result.Data.Change = ""
+			result.Data.Changes = nil
+		}
+		state = &monitorState{Scanned: start, Results: current}
+		if err := saveMonitorState(options.MonitorState, state); err != nil {
+			logger.Error("saving monitor state", "file", options.MonitorState, "error", err)
+		}
+
+		next := start.Add(options.MonitorInterval)
+		logger.Info("scan complete", "matched", stats.Matched, "next_scan", next)
+		select {
+		case <-time.After(time.Until(next)):
+		case <-ctx.Done():
+			return
+		}
+
+		// A fresh runner, its deduplication and stats being per scan.
+		if runner, err = inspector.New(&options.Options); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+	}
+}
+
+// Key identifying a result across scans.
+func monitorKey(result *inspector.Result) string {
+	return strings.Join([]string{result.Type, result.Data.Method, result.Host, result.Data.Source}, " ")
+}
+
+// Collect the ETag and Last-Modified validators of the previous results, to
+// request their URLs conditionally and skip the unchanged ones.
+func monitorValidators(state *monitorState) map[string]inspector.Validator {
+	validators := make(map[string]inspector.Validator)
+	for _, result := range state.Results {
+		if result.Type != inspector.TypeRequest || result.Data.Method != "" {
+			continue
+		}
+		if result.Data.ETag != "" || result.Data.LastModified != "" {
+			validators[result.Host] = inspector.Validator{ETag: result.Data.ETag, LastModified: result.Data.LastModified, Data: result.Data}
+		}
+	}
+	return validators
+}
+
+// List the changes of the compared fields of a result since the previous scan.
+func diffResults(previous, current *inspector.Result) []string {
+	var changes []string
+	compare := func(field string, before, after any) {
+		if before != after {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", field, changeValue(before), changeValue(after)))
+		}
+	}
+	compare("status_code", previous.Data.StatusCode, current.Data.StatusCode)
+	compare("content_length", previous.Data.ContentLength, current.Data.ContentLength)
+	compare("content_type", previous.Data.ContentType, current.Data.ContentType)
+	compare("suffix", previous.Data.Suffix, current.Data.Suffix)
+	compare("final_url", previous.Data.FinalURL, current.Data.FinalURL)
+	compare("etag", previous.Data.ETag, current.Data.ETag)
+	compare("last_modified", previous.Data.LastModified, current.Data.LastModified)
+	if !maps.Equal(previous.Data.Hashes, current.Data.Hashes) {
+		changes = append(changes, "hash")
+	}
+	return changes
+}
+
+// Format a compared value, quoting empty strings to keep them visible.
+func changeValue(value any) string {
+	if value == "" {
+		return `""`
+	}
+	return fmt.Sprint(value)
+}
+
+// Text tag of the change of a result, empty outside of monitor mode.
+func changeTag(result *inspector.Result) string {
+	switch result.Data.Change {
+	case "":
+		return ""
+	case changeChanged:
+		return "[changed: " + strings.Join(result.Data.Changes, ", ") + "]"
+	default:
+		return "[" + result.Data.Change + "]"
+	}
+}
+
+// Load the state of the previous scan, empty when there was none.
+func loadMonitorState(fileName string) (*monitorState, error) {
+	state := &monitorState{Results: make(map[string]*inspector.Result)}
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading monitor state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing monitor state %s: %w", fileName, err)
+	}
+	if state.Results == nil {
+		state.Results = make(map[string]*inspector.Result)
+	}
+	return state, nil
+}
+
+// Save the state of a scan, replacing the previous one at once so that an
+// interrupted write doesn't lose it.
+func saveMonitorState(fileName string, state *monitorState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), fileName)
+}
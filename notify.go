@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Endpoint of the Telegram Bot API.
+var telegramAPIURL = "https://api.telegram.org"
+
+const (
+	// Telegram allows 20 messages per minute in groups, results are batched
+	// into one message per interval.
+	telegramInterval   = 3 * time.Second
+	telegramMaxMessage = 4096 // Max length of a message.
+	telegramQueue      = 1000 // Results waiting to be sent before dropping them.
+)
+
+// Default template of the notified results.
+const defaultNotifyTemplate = "{{url}} [{{status}}] [{{length}}] [{{content_type}}] {{suffix}} {{change}}"
+
+// Sends the matched results to a Telegram chat in the background.
+type telegramNotifier struct {
+	token    string
+	chatID   string
+	template string
+	lines    chan string
+	done     chan struct{}
+	interval time.Duration
+	last     time.Time // Time the previous message was sent.
+}
+
+// Create the Telegram notifier of the options, the bot token and chat ID
+// defaulting to the TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID variables.
+func newTelegramNotifier(options *Options) (*telegramNotifier, error) {
+	n := &telegramNotifier{
+		token:    options.TelegramToken,
+		chatID:   options.TelegramChatID,
+		template: options.NotifyTemplate,
+		lines:    make(chan string, telegramQueue),
+		done:     make(chan struct{}),
+		interval: telegramInterval,
+	}
+	if n.token == "" {
+		n.token = os.Getenv("TELEGRAM_BOT_TOKEN")
+	}
+	if n.chatID == "" {
+		n.chatID = os.Getenv("TELEGRAM_CHAT_ID")
+	}
+	if n.token == "" || n.chatID == "" {
+		return nil, errors.New("telegram notifications need a bot token and a chat ID, set -telegram-token and -telegram-chat-id or TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID")
+	}
+	if n.template == "" {
+		n.template = defaultNotifyTemplate
+	}
+	go n.run()
+	return n, nil
+}
+
+// Queue a matched result, dropping it when the queue is full rather than
+// slowing the scan down.
+func (n *telegramNotifier) notify(result *inspector.Result) {
+	if result.Err != nil {
+		return
+	}
+	select {
+	case n.lines <- formatNotification(n.template, result):
+	default:
+	}
+}
+
+// Send the queued results and wait until they are sent.
+func (n *telegramNotifier) close() {
+	close(n.lines)
+	<-n.done
+}
+
+// Send the queued results in batches, one message per interval.
+func (n *telegramNotifier) run() {
+	defer close(n.done)
+	var carried string // Result not fitting in the previous message.
+	for {
+		message := carried
+		carried = ""
+		if message == "" {
+			line, ok := <-n.lines
+			if !ok {
+				return
+			}
+			message = line
+		}
+		// Fill the message with the results queued meanwhile.
+	fill:
+		for {
+			select {
+			case line, ok := <-n.lines:
+				if !ok {
+					break fill
+				}
+				if len(message)+1+len(line) > telegramMaxMessage {
+					carried = line
+					break fill
+				}
+				message += "\n" + line
+			default:
+				break fill
+			}
+		}
+		n.send(message)
+	}
+}
+
+// Send a message once the interval since the previous one is over, retrying
+// once when Telegram asks to slow down.
+func (n *telegramNotifier) send(message string) {
+	if len(message) > telegramMaxMessage {
+		message = message[:telegramMaxMessage]
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		time.Sleep(time.Until(n.last.Add(n.interval)))
+		n.last = time.Now()
+		retryAfter, err := n.sendMessage(message)
+		if err == nil {
+			return
+		}
+		if retryAfter == 0 || attempt > 0 {
+			logger.Error("sending Telegram notification", "error", err)
+			return
+		}
+		logger.Debug("Telegram rate limit reached", "retry_after", retryAfter)
+		n.last = time.Now().Add(retryAfter - n.interval)
+	}
+}
+
+// Call the sendMessage method, returning the delay Telegram asks to wait
+// before retrying when rate limited.
+func (n *telegramNotifier) sendMessage(message string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	form := url.Values{"chat_id": {n.chatID}, "text": {message}, "disable_web_page_preview": {"true"}}
+	req, err := http.NewRequestWithContext(ctx, "POST", telegramAPIURL+"/bot"+n.token+"/sendMessage", strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// The URL of the error holds the bot token.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if !reply.OK {
+		return time.Duration(reply.Parameters.RetryAfter) * time.Second, errors.New(reply.Description)
+	}
+	return 0, nil
+}
+
+// Format a result with a notification template.
+func formatNotification(template string, result *inspector.Result) string {
+	replacer := strings.NewReplacer(
+		"{{url}}", result.Host,
+		"{{status}}", strconv.FormatInt(result.Data.StatusCode, 10),
+		"{{length}}", strconv.FormatInt(result.Data.ContentLength, 10),
+		"{{content_type}}", result.Data.ContentType,
+		"{{suffix}}", result.Data.Suffix,
+		"{{source}}", result.Data.Source,
+		"{{final_url}}", result.Data.FinalURL,
+		"{{change}}", changeTag(result),
+	)
+	return strings.TrimSpace(replacer.Replace(template))
+}
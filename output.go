@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	outputQueue         = 1024                   // Results waiting to be written before slowing the scan down.
+	outputFlushInterval = 200 * time.Millisecond // Max time a written result stays buffered.
+)
+
+// Writes the results to stdout and the output file from a single goroutine,
+// so the lines of concurrent results never interleave. Writes are buffered
+// and flushed periodically.
+type outputWriter struct {
+	lines   chan string
+	done    chan struct{}
+	stdout  *bufio.Writer
+	file    *os.File
+	buffer  *bufio.Writer // Buffer of the output file.
+	errOnce sync.Once     // Only the first error writing the file is reported.
+}
+
+// Start writing to stdout and, when not nil, to the output file, which is
+// closed with the writer.
+func newOutputWriter(stdout io.Writer, file *os.File) *outputWriter {
+	w := &outputWriter{
+		lines:  make(chan string, outputQueue),
+		done:   make(chan struct{}),
+		stdout: bufio.NewWriter(stdout),
+		file:   file,
+	}
+	if file != nil {
+		w.buffer = bufio.NewWriter(file)
+	}
+	go w.run()
+	return w
+}
+
+// Queue the complete lines of a result to be written at once.
+func (w *outputWriter) write(lines string) {
+	w.lines <- lines
+}
+
+// Write the queued results, flush them and close the output file.
+func (w *outputWriter) close() {
+	close(w.lines)
+	<-w.done
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			w.reportError(err)
+		}
+	}
+}
+
+func (w *outputWriter) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(outputFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case lines, ok := <-w.lines:
+			if !ok {
+				w.flush()
+				return
+			}
+			w.stdout.WriteString(lines)
+			if w.buffer != nil {
+				if _, err := w.buffer.WriteString(lines); err != nil {
+					w.reportError(err)
+				}
+			}
+		case <-ticker.C:
+			w.flush()
+		}
+	}
+}
+
+func (w *outputWriter) flush() {
+	w.stdout.Flush()
+	if w.buffer != nil {
+		if err := w.buffer.Flush(); err != nil {
+			w.reportError(err)
+		}
+	}
+}
+
+// Report the first error writing the output file, the next ones usually
+// follow from it.
+func (w *outputWriter) reportError(err error) {
+	w.errOnce.Do(func() {
+		logger.Error("writing output file", "file", w.file.Name(), "error", err)
+	})
+}
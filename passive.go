@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Endpoints of the passive sources.
+var (
+	waybackCDXURL       = "https://web.archive.org/cdx/search/cdx"
+	commonCrawlIndexURL = "https://index.commoncrawl.org/collinfo.json"
+)
+
+const (
+	passiveTimeout = time.Minute // The archives can be slow to answer large queries.
+	passiveRetries = 3           // Retries of a page answered with 429 or 5xx.
+)
+
+// A source of historical URLs, queried at most once per interval.
+type passiveSource struct {
+	fetch    func(ctx context.Context, s *passiveSource, domain string, emit func(string) bool) error
+	interval time.Duration
+	last     time.Time
+}
+
+// Passive sources by name.
+var passiveSources = map[string]*passiveSource{
+	"wayback":     {fetch: fetchWayback, interval: time.Second},
+	"commoncrawl": {fetch: fetchCommonCrawl, interval: time.Second},
+}
+
+// Validate the passive sources names.
+func validatePassiveSources(names []string) error {
+	for _, name := range names {
+		if _, ok := passiveSources[strings.ToLower(strings.TrimSpace(name))]; !ok {
+			return fmt.Errorf("invalid passive source %q, use wayback or commoncrawl", name)
+		}
+	}
+	return nil
+}
+
+// Fetch the historical URLs of a domain and its subdomains from the passive
+// sources, calling emit for each URL not seen yet until it returns false. A
+// failing source is reported and the next one is queried.
+func fetchPassiveURLs(ctx context.Context, names []string, domain string, emit func(string) bool) {
+	domain = passiveDomain(domain)
+	if domain == "" {
+		return
+	}
+
+	seen := make(map[string]bool)
+	stopped := false
+	for _, name := range names {
+		source := passiveSources[strings.ToLower(strings.TrimSpace(name))]
+		err := source.fetch(ctx, source, domain, func(target string) bool {
+			if seen[target] {
+				return true
+			}
+			seen[target] = true
+			stopped = !emit(target)
+			return !stopped
+		})
+		if stopped || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Warn("fetching passive URLs", "source", name, "domain", domain, "error", err)
+			continue
+		}
+		logger.Debug("fetched passive URLs", "source", name, "domain", domain, "total", len(seen))
+	}
+}
+
+// Reduce an input line to the domain to query, dropping the scheme, port and path.
+func passiveDomain(input string) string {
+	if !strings.Contains(input, "://") {
+		input = "http://" + input
+	}
+	parsed, err := url.Parse(input)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "*.")
+}
+
+// Fetch the URLs archived by the Wayback Machine, page by page.
+func fetchWayback(ctx context.Context, s *passiveSource, domain string, emit func(string) bool) error {
+	query := waybackCDXURL + "?url=*." + url.QueryEscape(domain) + "/*&output=txt&fl=original&collapse=urlkey"
+	body, err := s.get(ctx, query+"&showNumPages=true")
+	if err != nil {
+		return err
+	}
+	pages, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil {
+		return fmt.Errorf("invalid number of pages %q", strings.TrimSpace(string(body)))
+	}
+
+	for page := 0; page < pages; page++ {
+		body, err := s.get(ctx, query+"&page="+strconv.Itoa(page))
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if target := strings.TrimSpace(scanner.Text()); target != "" && !emit(target) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// Fetch the URLs of the latest Common Crawl index, page by page.
+func fetchCommonCrawl(ctx context.Context, s *passiveSource, domain string, emit func(string) bool) error {
+	body, err := s.get(ctx, commonCrawlIndexURL)
+	if err != nil {
+		return err
+	}
+	var indexes []struct {
+		API string `json:"cdx-api"`
+	}
+	if err := json.Unmarshal(body, &indexes); err != nil {
+		return fmt.Errorf("parsing the index list: %w", err)
+	}
+	if len(indexes) == 0 {
+		return errors.New("no index available")
+	}
+
+	// The index list is sorted from the latest crawl.
+	query := indexes[0].API + "?url=*." + url.QueryEscape(domain) + "/*&output=json&fl=url"
+	body, err = s.get(ctx, query+"&showNumPages=true")
+	if errors.Is(err, errNoCaptures) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var info struct {
+		Pages int `json:"pages"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return fmt.Errorf("parsing the number of pages: %w", err)
+	}
+
+	for page := 0; page < info.Pages; page++ {
+		body, err := s.get(ctx, query+"&page="+strconv.Itoa(page))
+		if errors.Is(err, errNoCaptures) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var record struct {
+				URL string `json:"url"`
+			}
+			if json.Unmarshal(scanner.Bytes(), &record) == nil && record.URL != "" && !emit(record.URL) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// Returned by the Common Crawl index when a domain has no capture.
+var errNoCaptures = errors.New("no captures")
+
+// Get a page of a source, waiting for its rate limit and retrying the pages
+// answered with 429 or 5xx, after their Retry-After delay when given.
+func (s *passiveSource) get(ctx context.Context, target string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		if wait := time.Until(s.last.Add(s.interval)); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		s.last = time.Now()
+
+		reqCtx, cancel := context.WithTimeout(ctx, passiveTimeout)
+		req, err := http.NewRequestWithContext(reqCtx, "GET", target, nil)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return body, nil
+		case resp.StatusCode == http.StatusNotFound && strings.Contains(string(body), "No Captures found"):
+			return nil, errNoCaptures
+		case (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500) && attempt < passiveRetries:
+			delay := time.Duration(attempt+1) * 5 * time.Second
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				delay = time.Duration(seconds) * time.Second
+			}
+			logger.Debug("retrying passive source", "url", target, "status", resp.StatusCode, "delay", delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		default:
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Pauses and resumes the scans on the pause signal, SIGUSR1, printing the
+// statistics of the scan when pausing. In monitor mode, the next scans start
+// paused until resumed.
+type pauseController struct {
+	mu     sync.Mutex
+	runner *inspector.Runner // Runner of the current scan, nil between scans.
+	paused bool
+}
+
+// Start listening for the pause signal until ctx is done.
+func newPauseController(ctx context.Context) *pauseController {
+	p := &pauseController{}
+	if len(pauseSignals) == 0 {
+		return p
+	}
+	// Never stopped, as the default action of the signal kills the process.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, pauseSignals...)
+	logger.Debug("send SIGUSR1 to pause or resume the scan", "pid", os.Getpid())
+	go func() {
+		for {
+			select {
+			case <-signals:
+				p.toggle()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// Set the runner of the current scan, nil once it is over.
+func (p *pauseController) attach(runner *inspector.Runner) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.runner = runner
+	if runner != nil && p.paused {
+		runner.Pause()
+	}
+}
+
+func (p *pauseController) toggle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = !p.paused
+	if !p.paused {
+		if p.runner != nil {
+			p.runner.Resume()
+		}
+		logger.Info("scan resumed")
+		return
+	}
+	logger.Info("scan paused, send SIGUSR1 again to resume", "pid", os.Getpid())
+	if p.runner != nil {
+		p.runner.Pause()
+		printStats(p.runner.Stats())
+	}
+}
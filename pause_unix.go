@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals toggling the pause of the scan.
+var pauseSignals = []os.Signal{syscall.SIGUSR1}
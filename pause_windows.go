@@ -0,0 +1,6 @@
+package main
+
+import "os"
+
+// Windows has no user signals, scans can't be paused.
+var pauseSignals []os.Signal
@@ -0,0 +1,239 @@
+package inspector
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// A transport authenticating the requests with the configured credentials.
+// Digest and NTLM answer the challenge of a first response, the NTLM
+// handshake relying on the connection being kept alive. Redirects to
+// another host are sent without credentials.
+type authTransport struct {
+	next     http.RoundTripper
+	scheme   string // basic, bearer, digest or ntlm.
+	username string
+	password string
+	token    string
+}
+
+// Wrap the transport with the authentication of the options, only one
+// scheme can be set. The transport is returned as is without credentials.
+func newAuthTransport(next http.RoundTripper, options *Options) (http.RoundTripper, error) {
+	auth := &authTransport{next: next}
+	credentials := ""
+	for scheme, value := range map[string]string{
+		"basic":  options.AuthBasic,
+		"bearer": options.AuthBearer,
+		"digest": options.AuthDigest,
+		"ntlm":   options.AuthNTLM,
+	} {
+		if value == "" {
+			continue
+		}
+		if auth.scheme != "" {
+			return nil, fmt.Errorf("only one authentication scheme can be set")
+		}
+		auth.scheme, credentials = scheme, value
+	}
+
+	switch auth.scheme {
+	case "":
+		return next, nil
+	case "bearer":
+		auth.token = credentials
+	default:
+		var ok bool
+		auth.username, auth.password, ok = strings.Cut(credentials, ":")
+		if !ok || auth.username == "" {
+			return nil, fmt.Errorf("invalid %s credentials, expected user:pass", auth.scheme)
+		}
+	}
+	return auth, nil
+}
+
+func (a *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Credentials set with a custom Authorization header take precedence, and
+	// those of the options are kept from the hosts redirected to.
+	if req.Header.Get("Authorization") != "" || !strings.EqualFold(req.URL.Host, originHost(req)) {
+		return a.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	switch a.scheme {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+a.token)
+		return a.next.RoundTrip(req)
+	case "basic":
+		req.SetBasicAuth(a.username, a.password)
+		return a.next.RoundTrip(req)
+	case "ntlm":
+		req.Header.Set("Authorization", "NTLM "+ntlmNegotiate())
+	}
+
+	resp, err := a.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	authorization, ok := a.answerChallenge(req, resp.Header.Values("WWW-Authenticate"))
+	if !ok {
+		return resp, nil // Missing or unsupported challenge, keep the 401 response.
+	}
+
+	// Resend the request with its body rewound.
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		req.Body = body
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	req.Header.Set("Authorization", authorization)
+	return a.next.RoundTrip(req)
+}
+
+// Return the host of the request starting the redirect chain of a request.
+func originHost(req *http.Request) string {
+	for req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+	return req.URL.Host
+}
+
+// Compute the Authorization header answering the Digest or NTLM challenge of
+// the WWW-Authenticate headers.
+func (a *authTransport) answerChallenge(req *http.Request, headers []string) (string, bool) {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+
+	var authorization string
+	var err error
+	if a.scheme == "ntlm" {
+		challenge, ok := ntlmChallenge(headers)
+		if !ok {
+			return "", false
+		}
+		authorization, err = ntlmAuthenticate(challenge, a.username, a.password, nonce[:8], time.Now())
+		authorization = "NTLM " + authorization
+	} else {
+		challenge := digestChallenge(headers)
+		if challenge == nil {
+			return "", false
+		}
+		authorization, err = a.digestAuthorization(req, challenge, hex.EncodeToString(nonce))
+	}
+	return authorization, err == nil
+}
+
+// Parse the parameters of the Digest challenge among the WWW-Authenticate
+// headers, nil when there is none.
+func digestChallenge(headers []string) map[string]string {
+	for _, header := range headers {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(header), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+		challenge := make(map[string]string)
+		for params != "" {
+			var name, value string
+			name, params, _ = strings.Cut(strings.TrimLeft(params, " ,"), "=")
+			if strings.HasPrefix(params, `"`) {
+				// Quoted values may contain commas and escaped quotes.
+				var b strings.Builder
+				i := 1
+				for ; i < len(params) && params[i] != '"'; i++ {
+					if params[i] == '\\' && i+1 < len(params) {
+						i++
+					}
+					b.WriteByte(params[i])
+				}
+				value, params = b.String(), params[min(i+1, len(params)):]
+			} else {
+				value, params, _ = strings.Cut(params, ",")
+			}
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				challenge[name] = strings.TrimSpace(value)
+			}
+		}
+		return challenge
+	}
+	return nil
+}
+
+// Compute the Authorization header answering a Digest challenge (RFC 7616)
+// with the qop "auth" or without qop, using MD5 or SHA-256 and the given
+// client nonce.
+func (a *authTransport) digestAuthorization(req *http.Request, challenge map[string]string, cnonce string) (string, error) {
+	algorithm := challenge["algorithm"]
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %s", algorithm)
+	}
+	digest := func(parts ...string) string {
+		h := newHash()
+		h.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	var qop string
+	if challenge["qop"] != "" {
+		for _, option := range strings.Split(challenge["qop"], ",") {
+			if strings.TrimSpace(option) == "auth" {
+				qop = "auth"
+			}
+		}
+		if qop == "" {
+			return "", fmt.Errorf("unsupported digest qop %s", challenge["qop"])
+		}
+	}
+
+	nc := "00000001"
+	uri := req.URL.RequestURI()
+
+	ha1 := digest(a.username, challenge["realm"], a.password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = digest(ha1, challenge["nonce"], cnonce)
+	}
+	ha2 := digest(req.Method, uri)
+	response := digest(ha1, challenge["nonce"], ha2)
+	if qop != "" {
+		response = digest(ha1, challenge["nonce"], nc, cnonce, qop, ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf("username=%q", a.username),
+		fmt.Sprintf("realm=%q", challenge["realm"]),
+		fmt.Sprintf("nonce=%q", challenge["nonce"]),
+		fmt.Sprintf("uri=%q", uri),
+		"algorithm=" + algorithm,
+		fmt.Sprintf("response=%q", response),
+	}
+	if opaque, ok := challenge["opaque"]; ok {
+		fields = append(fields, fmt.Sprintf("opaque=%q", opaque))
+	}
+	if qop != "" {
+		fields = append(fields, "qop="+qop, "nc="+nc, fmt.Sprintf("cnonce=%q", cnonce))
+	}
+	return "Digest " + strings.Join(fields, ", "), nil
+}
@@ -0,0 +1,242 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDigestChallenge(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		want    map[string]string
+	}{
+		{
+			name:    "rfc 2617",
+			headers: []string{`Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`},
+			want: map[string]string{
+				"realm":  "testrealm@host.com",
+				"qop":    "auth,auth-int",
+				"nonce":  "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+				"opaque": "5ccc069c403ebaf9f0171e9517f40e41",
+			},
+		},
+		{
+			name:    "unquoted and escaped values",
+			headers: []string{`Basic realm="x"`, `digest Realm="a \"b\", c", algorithm=SHA-256,stale=false`},
+			want:    map[string]string{"realm": `a "b", c`, "algorithm": "SHA-256", "stale": "false"},
+		},
+		{name: "no digest", headers: []string{`Basic realm="x"`, "NTLM"}},
+		{name: "no header"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := digestChallenge(test.headers); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("digestChallenge() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDigestAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		password  string
+		challenge string
+		cnonce    string
+		response  string
+	}{
+		{
+			name:      "rfc 2617",
+			username:  "Mufasa",
+			password:  "Circle Of Life",
+			challenge: `Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`,
+			cnonce:    "0a4f113b",
+			response:  "6629fae49393a05397450978507c4ef1",
+		},
+		{
+			name:      "rfc 7616 md5",
+			username:  "Mufasa",
+			password:  "Circle of Life",
+			challenge: `Digest realm="http-auth@example.org", qop="auth, auth-int", algorithm=MD5, nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`,
+			cnonce:    "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ",
+			response:  "8ca523f5e9506fed4657c9700eebdbec",
+		},
+		{
+			name:      "rfc 7616 sha-256",
+			username:  "Mufasa",
+			password:  "Circle of Life",
+			challenge: `Digest realm="http-auth@example.org", qop="auth, auth-int", algorithm=SHA-256, nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`,
+			cnonce:    "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ",
+			response:  "753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			auth := &authTransport{scheme: "digest", username: test.username, password: test.password}
+			req := httptest.NewRequest("GET", "http://example.org/dir/index.html", nil)
+			header, err := auth.digestAuthorization(req, digestChallenge([]string{test.challenge}), test.cnonce)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(header, `response="`+test.response+`"`) {
+				t.Errorf("digestAuthorization() = %s, want response %s", header, test.response)
+			}
+			for _, field := range []string{`uri="/dir/index.html"`, "qop=auth", "nc=00000001", `cnonce="` + test.cnonce + `"`} {
+				if !strings.Contains(header, field) {
+					t.Errorf("digestAuthorization() = %s, missing %s", header, field)
+				}
+			}
+		})
+	}
+
+	auth := &authTransport{scheme: "digest", username: "user", password: "pass"}
+	req := httptest.NewRequest("GET", "http://example.org/", nil)
+	for _, challenge := range []string{`Digest realm="r", nonce="n", qop="auth-int"`, `Digest realm="r", nonce="n", algorithm=SHA-512`} {
+		if _, err := auth.digestAuthorization(req, digestChallenge([]string{challenge}), "c"); err == nil {
+			t.Errorf("digestAuthorization() accepted %s", challenge)
+		}
+	}
+}
+
+func TestMD4Sum(t *testing.T) {
+	// RFC 1320 test suite.
+	tests := map[string]string{
+		"":                           "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"a":                          "bde52cb31de33e46245e05fbdbd6fb24",
+		"abc":                        "a448017aaf21d8525fc10ae87aa6729d",
+		"message digest":             "d9130a8164549fe818874806e1c7014b",
+		"abcdefghijklmnopqrstuvwxyz": "d79e1c308aa5bbcdeea8ed63df412da9",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789":                   "043f8582f241db351ce627e153e7f0e4",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for input, want := range tests {
+		sum := md4Sum([]byte(input))
+		if got := hex.EncodeToString(sum[:]); got != want {
+			t.Errorf("md4Sum(%q) = %s, want %s", input, got, want)
+		}
+	}
+
+	// NTOWFv1 of MS-NLMP 4.2.2.1.2.
+	sum := md4Sum(utf16LE("Password"))
+	if got := hex.EncodeToString(sum[:]); got != "a4f49c406510bdcab6824ee7c30fd852" {
+		t.Errorf("md4Sum(Password) = %s", got)
+	}
+}
+
+// The NTLMv2 example of MS-NLMP 4.2.4.
+func TestNTLMAuthenticate(t *testing.T) {
+	var targetInfo []byte
+	for _, pair := range []struct {
+		id    uint16
+		value string
+	}{{2, "Domain"}, {1, "Server"}} {
+		targetInfo = binary.LittleEndian.AppendUint16(targetInfo, pair.id)
+		targetInfo = binary.LittleEndian.AppendUint16(targetInfo, uint16(len(utf16LE(pair.value))))
+		targetInfo = append(targetInfo, utf16LE(pair.value)...)
+	}
+	targetInfo = append(targetInfo, 0, 0, 0, 0)
+
+	challenge := make([]byte, 48)
+	copy(challenge, ntlmSignature)
+	binary.LittleEndian.PutUint32(challenge[8:], 2)
+	binary.LittleEndian.PutUint32(challenge[20:], 0xe28a8233)
+	copy(challenge[24:], []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef})
+	binary.LittleEndian.PutUint16(challenge[40:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint16(challenge[42:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(challenge[44:], 48)
+	challenge = append(challenge, targetInfo...)
+
+	clientChallenge := bytes.Repeat([]byte{0xaa}, 8)
+	filetimeZero := time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)
+	encoded, err := ntlmAuthenticate(challenge, `Domain\User`, "Password", clientChallenge, filetimeZero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg[:8], ntlmSignature) || binary.LittleEndian.Uint32(msg[8:]) != 3 {
+		t.Fatalf("not an authenticate message: %x", msg)
+	}
+	field := func(i int) []byte {
+		length := int(binary.LittleEndian.Uint16(msg[12+8*i:]))
+		offset := int(binary.LittleEndian.Uint32(msg[16+8*i:]))
+		return msg[offset : offset+length]
+	}
+
+	if got := hex.EncodeToString(field(0)); got != "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa" {
+		t.Errorf("LMv2 response = %s", got)
+	}
+	ntResponse := field(1)
+	if got := hex.EncodeToString(ntResponse[:16]); got != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("NTProofStr = %s", got)
+	}
+	if !bytes.Contains(ntResponse, targetInfo) {
+		t.Error("NTLMv2 response without the target info")
+	}
+	if !bytes.Equal(field(2), utf16LE("Domain")) || !bytes.Equal(field(3), utf16LE("User")) {
+		t.Errorf("domain %q and user %q", field(2), field(3))
+	}
+
+	if _, err := ntlmAuthenticate(challenge[:40], "User", "Password", clientChallenge, filetimeZero); err == nil {
+		t.Error("truncated challenge accepted")
+	}
+}
+
+func TestAuthTransportRedirect(t *testing.T) {
+	var leaked string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+
+	var sent []string
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/same":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/other":
+			http.Redirect(w, r, other.URL+"/final", http.StatusFound)
+		}
+	}))
+	defer origin.Close()
+
+	for _, options := range []*Options{{AuthBasic: "user:pass"}, {AuthBearer: "token"}} {
+		transport, err := newAuthTransport(http.DefaultTransport, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &http.Client{Transport: transport}
+
+		sent, leaked = nil, ""
+		resp, err := client.Get(origin.URL + "/same")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if len(sent) != 2 || sent[0] == "" || sent[1] != sent[0] {
+			t.Errorf("credentials sent to the origin: %q", sent)
+		}
+
+		resp, err = client.Get(origin.URL + "/other")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if leaked != "" {
+			t.Errorf("credentials sent to the host redirected to: %s", leaked)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package inspector
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Suffixes appended to a file name by editors and admins backing it up.
+var backupSuffixes = []string{"~", ".bak", ".old", ".orig", ".save", ".backup", ".copy", ".tmp", ".swp", ".zip"}
+
+// Extensions replacing the one of a file name (index.php becomes index.bak).
+var backupExtensions = []string{".bak", ".old", ".orig"}
+
+// Archive extensions of a whole directory.
+var backupArchives = []string{".zip", ".tar.gz", ".rar", ".7z"}
+
+// Generate the common backup variants of a URL: copies of the file left by
+// editors, and archives of its directory stored next to it or in its parent.
+// The URL itself is returned when it can't be parsed.
+func backupVariants(target string, defaultScheme string) []string {
+	normalized, err := NormalizeURL(target, defaultScheme)
+	if err != nil {
+		return []string{target}
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return []string{target}
+	}
+	parsed.RawQuery = ""
+
+	dir, name := path.Split(parsed.EscapedPath())
+	if dir == "" {
+		dir = "/"
+	}
+
+	var paths []string
+	if name != "" {
+		for _, suffix := range backupSuffixes {
+			paths = append(paths, dir+name+suffix)
+		}
+		if ext := path.Ext(name); ext != "" && ext != name {
+			base := strings.TrimSuffix(name, ext)
+			for _, backupExt := range backupExtensions {
+				paths = append(paths, dir+base+backupExt)
+			}
+		}
+		// Vim swap file of an open file.
+		paths = append(paths, dir+"."+name+".swp")
+	}
+
+	// Archives named after the directory, or after the host at the root.
+	dirName := path.Base(dir)
+	parent := path.Dir(strings.TrimSuffix(dir, "/"))
+	if dir == "/" {
+		dirName, parent = parsed.Hostname(), ""
+	}
+	for _, archive := range backupArchives {
+		paths = append(paths, strings.TrimSuffix(parent, "/")+"/"+dirName+archive)
+		if dir != "/" {
+			paths = append(paths, dir+dirName+archive)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var variants []string
+	for _, p := range paths {
+		variant := *parsed
+		variant.Path, _ = url.PathUnescape(p)
+		variant.RawPath = p
+		if s := variant.String(); !seen[s] {
+			seen[s] = true
+			variants = append(variants, s)
+		}
+	}
+	return variants
+}
@@ -0,0 +1,174 @@
+package inspector
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CDN and WAF providers recognized from response headers.
+var cdnHeaders = []struct {
+	provider string
+	header   string
+	contains string // empty when the header presence is enough
+}{
+	{"cloudflare", "Cf-Ray", ""},
+	{"cloudflare", "Server", "cloudflare"},
+	{"akamai", "Server", "akamaighost"},
+	{"akamai", "X-Akamai-Transformed", ""},
+	{"akamai", "Akamai-Grn", ""},
+	{"fastly", "X-Fastly-Request-Id", ""},
+	{"fastly", "Fastly-Debug-Digest", ""},
+	{"fastly", "X-Served-By", "cache-"},
+	{"cloudfront", "X-Amz-Cf-Id", ""},
+	{"cloudfront", "Via", "cloudfront"},
+	{"azure", "X-Azure-Ref", ""},
+	{"imperva", "X-Iinfo", ""},
+	{"imperva", "X-Cdn", "incapsula"},
+	{"sucuri", "X-Sucuri-Id", ""},
+	{"google", "Server", "gws"},
+	{"netlify", "X-Nf-Request-Id", ""},
+	{"vercel", "X-Vercel-Id", ""},
+	{"bunnycdn", "Server", "bunnycdn"},
+	{"keycdn", "Server", "keycdn"},
+}
+
+// CNAME suffixes of CDN edge hostnames.
+var cdnCNAMEs = map[string]string{
+	".cloudflare.net.":       "cloudflare",
+	".akamai.net.":           "akamai",
+	".akamaiedge.net.":       "akamai",
+	".edgekey.net.":          "akamai",
+	".edgesuite.net.":        "akamai",
+	".fastly.net.":           "fastly",
+	".fastlylb.net.":         "fastly",
+	".cloudfront.net.":       "cloudfront",
+	".azureedge.net.":        "azure",
+	".azurefd.net.":          "azure",
+	".incapdns.net.":         "imperva",
+	".sucuri.net.":           "sucuri",
+	".edgecastcdn.net.":      "edgecast",
+	".cdn77.org.":            "cdn77",
+	".b-cdn.net.":            "bunnycdn",
+	".kxcdn.com.":            "keycdn",
+	".stackpathdns.com.":     "stackpath",
+	".googlehosted.com.":     "google",
+	".vercel-dns.com.":       "vercel",
+	".netlifyglobalcdn.com.": "netlify",
+}
+
+// Published edge IP ranges of the largest providers.
+var cdnRanges = map[string][]string{
+	"cloudflare": {
+		"173.245.48.0/20", "103.21.244.0/22", "103.22.200.0/22", "103.31.4.0/22",
+		"141.101.64.0/18", "108.162.192.0/18", "190.93.240.0/20", "188.114.96.0/20",
+		"197.234.240.0/22", "198.41.128.0/17", "162.158.0.0/15", "104.16.0.0/13",
+		"104.24.0.0/14", "172.64.0.0/13", "131.0.72.0/22",
+		"2400:cb00::/32", "2606:4700::/32", "2803:f800::/32", "2405:b500::/32",
+		"2405:8100::/32", "2a06:98c0::/29", "2c0f:f248::/32",
+	},
+	"fastly": {
+		"23.235.32.0/20", "43.249.72.0/22", "103.244.50.0/24", "103.245.222.0/23",
+		"103.245.224.0/24", "104.156.80.0/20", "140.248.64.0/18", "140.248.128.0/17",
+		"146.75.0.0/17", "151.101.0.0/16", "157.52.64.0/18", "167.82.0.0/17",
+		"167.82.128.0/20", "167.82.160.0/20", "167.82.224.0/20", "172.111.64.0/18",
+		"185.31.16.0/22", "199.27.72.0/21", "199.232.0.0/16",
+		"2a04:4e40::/32", "2a04:4e42::/32",
+	},
+}
+
+// Parsed cdnRanges, built on first use.
+var (
+	cdnNetworksOnce sync.Once
+	cdnNetworks     map[string][]*net.IPNet
+)
+
+// Detects CDNs and caches the DNS based lookups per host.
+type cdnDetector struct {
+	resolver *net.Resolver
+	cache    sync.Map // host -> provider
+}
+
+// Return the CDN or WAF fronting a response, or an empty string.
+func (d *cdnDetector) detect(ctx context.Context, host string, header http.Header) string {
+	if provider := cdnFromHeaders(header); provider != "" {
+		return provider
+	}
+
+	if cached, ok := d.cache.Load(host); ok {
+		return cached.(string)
+	}
+	provider := d.cdnFromDNS(ctx, host)
+	d.cache.Store(host, provider)
+	return provider
+}
+
+// Match the response headers against the known CDN headers.
+func cdnFromHeaders(header http.Header) string {
+	for _, h := range cdnHeaders {
+		value := header.Get(h.header)
+		if value == "" {
+			continue
+		}
+		if h.contains == "" || strings.Contains(strings.ToLower(value), h.contains) {
+			return h.provider
+		}
+	}
+	return ""
+}
+
+// Match the CNAME and the resolved addresses of host against the known CDNs.
+func (d *cdnDetector) cdnFromDNS(ctx context.Context, host string) string {
+	if net.ParseIP(host) == nil {
+		if cname, err := d.resolver.LookupCNAME(ctx, host); err == nil {
+			for suffix, provider := range cdnCNAMEs {
+				if strings.HasSuffix(strings.ToLower(cname), suffix) {
+					return provider
+				}
+			}
+		}
+	}
+
+	ips, err := d.resolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return ""
+	}
+	return cdnFromIPs(ips)
+}
+
+// Match addresses against the published CDN ranges.
+func cdnFromIPs(ips []net.IP) string {
+	cdnNetworksOnce.Do(func() {
+		cdnNetworks = make(map[string][]*net.IPNet)
+		for provider, ranges := range cdnRanges {
+			for _, cidr := range ranges {
+				if _, network, err := net.ParseCIDR(cidr); err == nil {
+					cdnNetworks[provider] = append(cdnNetworks[provider], network)
+				}
+			}
+		}
+	})
+
+	for _, ip := range ips {
+		for provider, networks := range cdnNetworks {
+			for _, network := range networks {
+				if network.Contains(ip) {
+					return provider
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Report whether a content type is a static asset commonly served by CDNs.
+func isStaticAsset(contentType string) bool {
+	for _, prefix := range []string{"image/", "video/", "audio/", "font/", "application/font-", "text/css", "text/javascript", "application/javascript", "application/x-javascript"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package inspector
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Split a Content-Type header into its lower case media type and charset,
+// the other parameters being dropped. The charset of headers with malformed
+// parameters is still looked for.
+func parseContentType(header string) (mediaType string, charset string) {
+	mediaType, params, err := mime.ParseMediaType(header)
+	if err == nil {
+		return strings.ToLower(mediaType), strings.ToLower(params["charset"])
+	}
+	parts := strings.Split(header, ";")
+	for _, part := range parts[1:] {
+		if name, value, ok := strings.Cut(part, "="); ok && strings.EqualFold(strings.TrimSpace(name), "charset") {
+			charset = strings.ToLower(strings.Trim(strings.TrimSpace(value), `"'`))
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(parts[0])), charset
+}
+
+// Report whether a media type holds text, whose charset matters.
+func isTextType(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+xml"), strings.HasSuffix(mediaType, "+json"),
+		strings.Contains(mediaType, "javascript"), strings.Contains(mediaType, "ecmascript"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/x-www-form-urlencoded", "application/x-php", "application/sql":
+		return true
+	}
+	return false
+}
+
+var (
+	// <meta charset="utf-8"> and <meta http-equiv="Content-Type" content="text/html; charset=utf-8">.
+	metaCharsetRegex = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([\w.:-]+)`)
+	// <?xml version="1.0" encoding="ISO-8859-1"?>
+	xmlEncodingRegex = regexp.MustCompile(`(?i)^\s*<\?xml[^>]+encoding\s*=\s*["']([\w.:-]+)`)
+)
+
+// Detect the charset of a body sent without one, from its byte order mark,
+// HTML meta tag or XML declaration, else utf-8 when it is valid UTF-8. It
+// returns an empty string when unknown.
+func detectCharset(body []byte) string {
+	switch {
+	case len(body) == 0:
+		return ""
+	case bytes.HasPrefix(body, []byte("\xef\xbb\xbf")):
+		return "utf-8"
+	case bytes.HasPrefix(body, []byte("\xff\xfe")):
+		return "utf-16le"
+	case bytes.HasPrefix(body, []byte("\xfe\xff")):
+		return "utf-16be"
+	}
+	// Declarations are at the start of the document.
+	head := body[:min(len(body), 1024)]
+	if match := xmlEncodingRegex.FindSubmatch(head); match != nil {
+		return strings.ToLower(string(match[1]))
+	}
+	if match := metaCharsetRegex.FindSubmatch(head); match != nil {
+		return strings.ToLower(string(match[1]))
+	}
+	if utf8.Valid(body) {
+		return "utf-8"
+	}
+	return ""
+}
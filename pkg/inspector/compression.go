@@ -0,0 +1,119 @@
+package inspector
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content encodings accepted by the Compression option, those decodeBody
+// decompresses. Brotli has no decoder in the standard library.
+var compressionEncodings = []string{"gzip", "deflate", "zstd", "identity"}
+
+// Build the Accept-Encoding header sent for the Compression option, empty
+// to let the transport ask for gzip and decompress it transparently.
+func acceptEncoding(options *Options) (string, error) {
+	var encodings []string
+	for _, value := range options.Compression {
+		encoding := strings.ToLower(strings.TrimSpace(value))
+		if encoding == "" {
+			continue
+		}
+		if !slices.Contains(compressionEncodings, encoding) {
+			return "", fmt.Errorf("invalid compression %s, use %s", value, strings.Join(compressionEncodings, ", "))
+		}
+		encodings = append(encodings, encoding)
+	}
+	return strings.Join(encodings, ", "), nil
+}
+
+// Report the content encoding of a response, empty when not compressed.
+// The transport removes the header of the gzip bodies it decompressed.
+func responseEncoding(resp *http.Response) string {
+	if resp.Uncompressed {
+		return "gzip"
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "identity" {
+		return ""
+	}
+	return encoding
+}
+
+// Counts the bytes read from a response body.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// Wrap a body to decompress the given content encoding, unknown encodings
+// being an error.
+func decodeBody(body io.Reader, encoding string) (io.Reader, error) {
+	switch encoding {
+	case "":
+		return body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "deflate":
+		// Deflate is meant to be zlib wrapped but some servers send it raw.
+		buffered := bufio.NewReader(body)
+		header, err := buffered.Peek(2)
+		if err != nil {
+			return nil, err
+		}
+		if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+	case "zstd":
+		// A single goroutine decodes synchronously, with nothing to release.
+		return zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %s", encoding)
+	}
+}
+
+// Read a response body up to limit decompressed bytes, decoding the content
+// encodings the transport left. When data is set, the encoding and, once the
+// whole body is read, the transfer and decompressed sizes are recorded.
+func readDecoded(resp *http.Response, limit int, data *Data) []byte {
+	encoding := responseEncoding(resp)
+	counter := &countingReader{reader: resp.Body}
+	var reader io.Reader = counter
+	if !resp.Uncompressed {
+		decoded, err := decodeBody(counter, encoding)
+		if err != nil {
+			if data != nil {
+				data.ContentEncoding = encoding
+			}
+			return nil
+		}
+		reader = decoded
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, int64(limit)))
+	if data == nil || encoding == "" {
+		return body
+	}
+	data.ContentEncoding = encoding
+	// The transport hides the compressed size of the bodies it decompressed.
+	if err == nil && len(body) < limit && !resp.Uncompressed {
+		data.TransferSize = counter.count
+		data.DecompressedSize = int64(len(body))
+	}
+	return body
+}
@@ -0,0 +1,41 @@
+package inspector
+
+import (
+	"net/http"
+	"time"
+)
+
+// Validator holds the ETag and Last-Modified date, in RFC 3339, of a
+// previous response to a URL, along with the details it was reported with.
+type Validator struct {
+	ETag         string
+	LastModified string
+	Data         Data // Reported again, with NotModified set, on a 304 response.
+}
+
+// SetValidators makes the requests to the given URLs conditional, a 304
+// response being reported with the Data of its validator, which goes
+// through the matchers but the body regexes. It must be called before Run.
+func (r *Runner) SetValidators(validators map[string]Validator) {
+	r.validators = validators
+}
+
+// Build the If-None-Match and If-Modified-Since headers of a URL, nil when
+// there is no validator for it.
+func (r *Runner) conditionalHeaders(target string) http.Header {
+	validator, ok := r.validators[target]
+	if !ok {
+		return nil
+	}
+	header := make(http.Header)
+	if validator.ETag != "" {
+		header.Set("If-None-Match", validator.ETag)
+	}
+	if modified, err := time.Parse(time.RFC3339, validator.LastModified); err == nil {
+		header.Set("If-Modified-Since", modified.UTC().Format(http.TimeFormat))
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	return header
+}
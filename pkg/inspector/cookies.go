@@ -0,0 +1,129 @@
+package inspector
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cookies sent with every request: the cookies of the options, sent to the
+// requested host, and the cookies of the cookie file, sent to their domain.
+type cookieSource struct {
+	static  []*http.Cookie
+	file    map[url.URL][]*http.Cookie // Keyed by the URL of their domain.
+	persist bool                       // Keep the cookies set along a redirect chain.
+}
+
+// Load the cookies of the options and of the Netscape cookie file, nil when
+// no cookies are configured and not persisting them.
+func loadCookies(options *Options) (*cookieSource, error) {
+	if options.Cookie == "" && options.CookieFile == "" && !options.PersistCookies {
+		return nil, nil
+	}
+
+	source := &cookieSource{file: make(map[url.URL][]*http.Cookie), persist: options.PersistCookies}
+	if options.Cookie != "" {
+		cookies, err := http.ParseCookie(options.Cookie)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie %q: %w", options.Cookie, err)
+		}
+		for _, cookie := range cookies {
+			cookie.Path = "/"
+			source.static = append(source.static, cookie)
+		}
+	}
+
+	if options.CookieFile != "" {
+		file, err := os.Open(options.CookieFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening cookie file %s: %w", options.CookieFile, err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			domainURL, cookie, err := parseNetscapeCookie(scanner.Text())
+			if err != nil {
+				return nil, fmt.Errorf("cookie file %s line %d: %w", options.CookieFile, lineNumber, err)
+			}
+			if cookie != nil {
+				source.file[domainURL] = append(source.file[domainURL], cookie)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading cookie file %s: %w", options.CookieFile, err)
+		}
+	}
+	return source, nil
+}
+
+// Parse a line of a Netscape cookie file: domain, include subdomains, path,
+// secure, expiry, name and value separated by tabs. Comments and blank
+// lines return a nil cookie.
+func parseNetscapeCookie(line string) (url.URL, *http.Cookie, error) {
+	line = strings.TrimRight(line, "\r\n")
+	httpOnly := strings.HasPrefix(line, "#HttpOnly_")
+	line = strings.TrimPrefix(line, "#HttpOnly_")
+	if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+		return url.URL{}, nil, nil
+	}
+
+	fields := strings.Split(line, "\t")
+	if len(fields) != 7 {
+		return url.URL{}, nil, fmt.Errorf("expected 7 tab separated fields, got %d", len(fields))
+	}
+	expiry, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return url.URL{}, nil, fmt.Errorf("invalid expiry %q", fields[4])
+	}
+
+	domain := strings.TrimPrefix(fields[0], ".")
+	cookie := &http.Cookie{
+		Name:     fields[5],
+		Value:    fields[6],
+		Path:     fields[2],
+		Secure:   strings.EqualFold(fields[3], "TRUE"),
+		HttpOnly: httpOnly,
+	}
+	if strings.EqualFold(fields[1], "TRUE") {
+		cookie.Domain = domain // Host-only cookies have no domain.
+	}
+	if expiry > 0 {
+		cookie.Expires = time.Unix(expiry, 0)
+	}
+
+	scheme := "http"
+	if cookie.Secure {
+		scheme = "https"
+	}
+	return url.URL{Scheme: scheme, Host: domain, Path: "/"}, cookie, nil
+}
+
+// Build the cookie jar of a request chain starting at target. Cookies set by
+// the responses are only kept when persisting them.
+func (c *cookieSource) jar(target *url.URL) http.CookieJar {
+	jar, _ := cookiejar.New(nil)
+	for domainURL, cookies := range c.file {
+		jar.SetCookies(&domainURL, cookies)
+	}
+	if len(c.static) > 0 {
+		jar.SetCookies(target, c.static)
+	}
+	if !c.persist {
+		return readOnlyJar{jar}
+	}
+	return jar
+}
+
+// A cookie jar ignoring the cookies set by responses.
+type readOnlyJar struct {
+	http.CookieJar
+}
+
+func (readOnlyJar) SetCookies(*url.URL, []*http.Cookie) {}
@@ -0,0 +1,95 @@
+package inspector
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Values of the href and src attributes, quoted or not.
+var linkRegex = regexp.MustCompile(`(?i)\b(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// The crawl started from an input URL: the pages already queued and the
+// number of pages left in its budget.
+type crawl struct {
+	host   string // Hostname of the input URL, the crawl stays on it.
+	mu     sync.Mutex
+	seen   map[string]bool
+	budget int
+}
+
+// Start the crawl of an input URL.
+func newCrawl(target string, options *Options) *crawl {
+	c := &crawl{host: hostOf(target), seen: make(map[string]bool), budget: options.CrawlBudget}
+	if normalized, err := NormalizeURL(target, options.DefaultScheme); err == nil {
+		c.seen[normalized] = true
+	}
+	return c
+}
+
+// Select the links of a page at the given depth to inspect next: links not
+// queued yet, in scope, or on the host of the input URL without scope rules,
+// while the depth and the page budget allow it.
+func (c *crawl) follow(r *Runner, depth int, links []string) []string {
+	if depth >= r.options.CrawlDepth {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var next []string
+	for _, link := range links {
+		if c.budget <= 0 {
+			break
+		}
+		if c.seen[link] {
+			continue
+		}
+		c.seen[link] = true
+
+		if r.scope != nil {
+			if !r.scope.allows(link, r.options.DefaultScheme) {
+				continue
+			}
+		} else if !strings.EqualFold(hostOf(link), c.host) {
+			continue
+		}
+		c.budget--
+		next = append(next, link)
+	}
+	return next
+}
+
+// Report whether a result is an HTML page.
+func isHTML(result *Result) bool {
+	return strings.Contains(result.Data.ContentType, "html")
+}
+
+// Extract the http(s) links of the href and src attributes of an HTML page,
+// resolved against its URL and without fragment.
+func extractLinks(base *url.URL, body []byte) []string {
+	seen := make(map[string]bool)
+	var links []string
+	for _, match := range linkRegex.FindAllSubmatch(body, -1) {
+		value := strings.TrimSpace(html.UnescapeString(string(match[1]) + string(match[2]) + string(match[3])))
+		if value == "" || strings.HasPrefix(value, "#") {
+			continue
+		}
+		ref, err := url.Parse(value)
+		if err != nil {
+			continue
+		}
+		link := base.ResolveReference(ref)
+		if link.Scheme != "http" && link.Scheme != "https" {
+			continue // javascript:, mailto:, data: and the like.
+		}
+		link.Fragment, link.RawFragment = "", ""
+		if s := link.String(); !seen[s] {
+			seen[s] = true
+			links = append(links, s)
+		}
+	}
+	return links
+}
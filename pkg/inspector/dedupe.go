@@ -0,0 +1,68 @@
+package inspector
+
+import (
+	"crypto/sha1"
+	"fmt"
+)
+
+// Collapse the results of each host sharing the same status code, length and
+// body hash into the first one, counting the collapsed results. Results are
+// held until in is closed as any later result may be a duplicate, failed
+// inspections are forwarded right away. The held bodies are dropped once
+// hashed unless keepBodies is set, for a later stage comparing them.
+func collapseDuplicates(in <-chan *Result, out chan<- *Result, keepBodies bool) {
+	defer close(out)
+
+	var representatives []*Result
+	seen := make(map[string]*Result)
+	for result := range in {
+		if result.Err != nil || result.Type != TypeRequest {
+			out <- result
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%d|%d|%x", hostOf(result.Host), result.Data.StatusCode, len(result.Body), sha1.Sum(result.Body))
+		if representative, ok := seen[key]; ok {
+			representative.Data.Duplicates++
+			continue
+		}
+		if !keepBodies {
+			result.Body = nil
+		}
+		result.Data.Duplicates = 1
+		seen[key] = result
+		representatives = append(representatives, result)
+	}
+
+	for _, result := range representatives {
+		if result.Data.Duplicates == 1 {
+			result.Data.Duplicates = 0 // Only collapsed results report a count.
+		}
+		out <- result
+	}
+}
+
+// Forward only the first result of each host, or of each response body in
+// hash mode. Failed inspections and results without a body in hash mode are
+// forwarded as is.
+func uniqueResults(in <-chan *Result, out chan<- *Result, mode string) {
+	defer close(out)
+
+	seen := make(map[string]bool)
+	for result := range in {
+		if result.Err != nil || (mode == "hash" && result.Body == nil) {
+			out <- result
+			continue
+		}
+
+		key := hostOf(result.Host)
+		if mode == "hash" {
+			key = fmt.Sprintf("%x", sha1.Sum(result.Body))
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out <- result
+	}
+}
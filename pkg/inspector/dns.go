@@ -0,0 +1,154 @@
+package inspector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ErrUnresolvable is returned when the host of a URL does not resolve.
+var ErrUnresolvable = errors.New("unresolvable host")
+
+// Max number of CNAME records followed for a host.
+const maxCNAMEs = 10
+
+// DNSInfo holds the DNS resolution details of a host.
+type DNSInfo struct {
+	IPs    []string `json:"ips,omitempty"`
+	CNAMEs []string `json:"cname,omitempty"`
+}
+
+// Resolves hosts with the system or the custom DNS servers and caches the
+// details per host.
+type dnsResolver struct {
+	resolver *net.Resolver
+	servers  []string // empty when using the system configuration
+	next     atomic.Uint64
+	cache    sync.Map // host -> *DNSInfo, nil when unresolvable
+}
+
+// Create a resolver using the DNS servers listed in the resolvers file, one
+// "ip" or "ip:port" per line, or the system resolver when no file is given.
+func newDNSResolver(options *Options) (*dnsResolver, error) {
+	d := &dnsResolver{resolver: net.DefaultResolver}
+	if options.ResolversFile == "" {
+		return d, nil
+	}
+
+	file, err := os.Open(options.ResolversFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening resolvers file %s: %w", options.ResolversFile, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(line); err != nil {
+			line = net.JoinHostPort(line, "53")
+		}
+		d.servers = append(d.servers, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading resolvers file %s: %w", options.ResolversFile, err)
+	}
+	if len(d.servers) == 0 {
+		return nil, fmt.Errorf("no resolvers found in %s", options.ResolversFile)
+	}
+
+	// The pure Go resolver sends every query to the next custom server in turn.
+	dialer := &net.Dialer{Timeout: time.Duration(options.Timeout) * time.Second}
+	d.resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, d.server())
+		},
+	}
+	return d, nil
+}
+
+// Pick the next custom DNS server.
+func (d *dnsResolver) server() string {
+	return d.servers[(d.next.Add(1)-1)%uint64(len(d.servers))]
+}
+
+// Resolve a host and return its addresses and CNAME chain, or ErrUnresolvable.
+func (d *dnsResolver) lookup(ctx context.Context, host string) (*DNSInfo, error) {
+	if cached, ok := d.cache.Load(host); ok {
+		if cached.(*DNSInfo) == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnresolvable, host)
+		}
+		return cached.(*DNSInfo), nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return &DNSInfo{IPs: []string{ip.String()}}, nil
+	}
+
+	addrs, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			d.cache.Store(host, (*DNSInfo)(nil))
+			return nil, fmt.Errorf("%w: %s", ErrUnresolvable, host)
+		}
+		return nil, err // Timeouts and server failures are not cached.
+	}
+
+	info := &DNSInfo{CNAMEs: d.cnameChain(ctx, host)}
+	for _, addr := range addrs {
+		info.IPs = append(info.IPs, addr.IP.String())
+	}
+	d.cache.Store(host, info)
+	return info, nil
+}
+
+// Follow the CNAME records of a host. The standard resolver only exposes the
+// final canonical name, so each hop is queried directly.
+func (d *dnsResolver) cnameChain(ctx context.Context, host string) []string {
+	servers := d.servers
+	if len(servers) == 0 {
+		config, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil || len(config.Servers) == 0 {
+			return nil
+		}
+		servers = []string{net.JoinHostPort(config.Servers[0], config.Port)}
+	}
+
+	client := &dns.Client{}
+	var chain []string
+	name := dns.Fqdn(host)
+	for i := 0; i < maxCNAMEs; i++ {
+		msg := new(dns.Msg)
+		msg.SetQuestion(name, dns.TypeCNAME)
+		resp, _, err := client.ExchangeContext(ctx, msg, servers[i%len(servers)])
+		if err != nil || len(resp.Answer) == 0 {
+			break
+		}
+		cname, ok := resp.Answer[0].(*dns.CNAME)
+		if !ok {
+			break
+		}
+		name = cname.Target
+		chain = append(chain, strings.TrimSuffix(name, "."))
+	}
+	return chain
+}
+
+// Report whether a request error comes from a host that does not resolve.
+func isUnresolvable(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
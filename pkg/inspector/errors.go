@@ -0,0 +1,75 @@
+package inspector
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// Error types of failed inspections.
+const (
+	ErrorTypeInvalidURL = "invalid-url"
+	ErrorTypeDNS        = "dns"
+	ErrorTypeBlocked    = "blocked"
+	ErrorTypeTimeout    = "timeout"
+	ErrorTypeTLS        = "tls"
+	ErrorTypeRefused    = "refused"
+	ErrorTypeReset      = "reset"
+	ErrorTypeOther      = "other"
+)
+
+var errorTypes = []string{ErrorTypeInvalidURL, ErrorTypeDNS, ErrorTypeBlocked, ErrorTypeTimeout, ErrorTypeTLS, ErrorTypeRefused, ErrorTypeReset, ErrorTypeOther}
+
+// ErrorType classifies the error of a failed inspection, it returns an empty
+// string for a nil error.
+func ErrorType(err error) string {
+	var (
+		dnsErr         *net.DNSError
+		netErr         net.Error
+		recordErr      tls.RecordHeaderError
+		alertErr       tls.AlertError
+		verifyErr      *tls.CertificateVerificationError
+		authorityErr   x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		certInvalidErr x509.CertificateInvalidError
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrInvalidURL):
+		return ErrorTypeInvalidURL
+	case errors.Is(err, ErrUnresolvable), errors.As(err, &dnsErr):
+		return ErrorTypeDNS
+	case errors.Is(err, ErrBlockedAddress):
+		return ErrorTypeBlocked
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTypeTimeout
+	case errors.As(err, &recordErr), errors.As(err, &alertErr), errors.As(err, &verifyErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &certInvalidErr):
+		return ErrorTypeTLS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorTypeRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return ErrorTypeReset
+	}
+	return ErrorTypeOther
+}
+
+// Check the comma separated error types of the error matchers.
+func validateErrorTypes(value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, errorType := range strings.Split(value, ",") {
+		if !matches(strings.TrimSpace(errorType), strings.Join(errorTypes, ",")) {
+			return fmt.Errorf("invalid error type %q, use %s", strings.TrimSpace(errorType), strings.Join(errorTypes, ", "))
+		}
+	}
+	return nil
+}
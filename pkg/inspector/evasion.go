@@ -0,0 +1,70 @@
+package inspector
+
+import (
+	"math/rand/v2"
+	"net"
+	"net/http"
+)
+
+// Accept-Language values of browsers in various locales.
+var acceptLanguages = []string{
+	"en-US,en;q=0.9",
+	"en-GB,en;q=0.9",
+	"en-US,en;q=0.9,es;q=0.8",
+	"fr-FR,fr;q=0.9,en-US;q=0.8,en;q=0.7",
+	"de-DE,de;q=0.9,en;q=0.8",
+	"es-ES,es;q=0.9,en;q=0.8",
+	"it-IT,it;q=0.9,en-US;q=0.8,en;q=0.7",
+	"pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7",
+	"nl-NL,nl;q=0.9,en;q=0.8",
+	"ja-JP,ja;q=0.9,en-US;q=0.8,en;q=0.7",
+	"zh-CN,zh;q=0.9,en;q=0.8",
+	"ko-KR,ko;q=0.9,en-US;q=0.8,en;q=0.7",
+	"ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7",
+	"pl-PL,pl;q=0.9,en-US;q=0.8,en;q=0.7",
+	"tr-TR,tr;q=0.9,en-US;q=0.8,en;q=0.7",
+}
+
+// Optional headers sent by browsers, each request sending a random subset
+// so they don't all share the same header fingerprint. Only the subset
+// varies: net/http writes the headers of a request sorted by name.
+var optionalHeaders = []struct{ name, value string }{
+	{"Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8"},
+	{"Cache-Control", "max-age=0"},
+	{"DNT", "1"},
+	{"Upgrade-Insecure-Requests", "1"},
+	{"Sec-Fetch-Dest", "document"},
+	{"Sec-Fetch-Mode", "navigate"},
+	{"Sec-Fetch-Site", "none"},
+	{"Sec-Fetch-User", "?1"},
+	{"Pragma", "no-cache"},
+}
+
+// Set the randomized headers of the evasion options, before the custom
+// headers which take precedence.
+func (r *Runner) setEvasionHeaders(req *http.Request) {
+	if r.options.RandomAcceptLanguage {
+		req.Header.Set("Accept-Language", acceptLanguages[rand.IntN(len(acceptLanguages))])
+	}
+	if r.options.VaryHeaders {
+		for _, header := range optionalHeaders {
+			if rand.IntN(2) == 0 {
+				req.Header.Set(header.name, header.value)
+			}
+		}
+	}
+	if r.options.SpoofForwardedFor {
+		req.Header.Set("X-Forwarded-For", randomPublicIP())
+	}
+}
+
+// Return a random public IPv4 address.
+func randomPublicIP() string {
+	for {
+		ip := net.IPv4(byte(1+rand.IntN(223)), byte(rand.IntN(256)), byte(rand.IntN(256)), byte(1+rand.IntN(254)))
+		// Skip the internal ranges, and 100.64.0.0/10 of carrier-grade NAT.
+		if !isInternalIP(ip) && !(ip[12] == 100 && ip[13]&0xc0 == 64) {
+			return ip.String()
+		}
+	}
+}
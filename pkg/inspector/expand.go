@@ -0,0 +1,148 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Max number of URLs discovered per origin when expanding.
+const maxExpandedURLs = 5000
+
+// A URL discovered in the robots.txt or a sitemap of an input URL.
+type expandedURL struct {
+	target string
+	source string // URL of the robots.txt or sitemap listing it.
+}
+
+// Discover the URLs listed in the robots.txt and sitemaps of the origin of
+// target, once per origin. Sitemaps are /sitemap.xml and the ones declared
+// in robots.txt, sitemap indexes are not followed.
+func (r *Runner) expand(ctx context.Context, target string) []expandedURL {
+	normalized, err := NormalizeURL(target, r.options.DefaultScheme)
+	if err != nil {
+		return nil
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return nil
+	}
+	origin := parsed.Scheme + "://" + parsed.Host
+	if _, expanded := r.expanded.LoadOrStore(origin, true); expanded {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var discovered []expandedURL
+	add := func(target string, source string) {
+		if !seen[target] && len(discovered) < maxExpandedURLs {
+			seen[target] = true
+			discovered = append(discovered, expandedURL{target: target, source: source})
+		}
+	}
+
+	sitemaps := []string{origin + "/sitemap.xml"}
+	robotsURL := origin + "/robots.txt"
+	if body := r.fetchListing(ctx, robotsURL); body != nil {
+		paths, declared := parseRobots(body)
+		for _, path := range paths {
+			add(origin+path, robotsURL)
+		}
+		sitemaps = append(sitemaps, declared...)
+	}
+
+	fetched := make(map[string]bool)
+	for _, sitemap := range sitemaps {
+		if fetched[sitemap] {
+			continue
+		}
+		fetched[sitemap] = true
+		if body := r.fetchListing(ctx, sitemap); body != nil {
+			for _, loc := range sitemapLocs(body) {
+				add(loc, sitemap)
+			}
+		}
+	}
+	return discovered
+}
+
+// Fetch a robots.txt or sitemap, nil unless it answers 200. Gzip compressed
+// sitemaps are decompressed.
+func (r *Runner) fetchListing(ctx context.Context, target string) []byte {
+	resp, err := r.doRequest(ctx, "GET", target)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	body := readDecoded(resp, r.options.MaxBodySize, nil)
+	if bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil
+		}
+		body, _ = io.ReadAll(io.LimitReader(reader, int64(r.options.MaxBodySize)))
+	}
+	return body
+}
+
+// Parse the Allow and Disallow paths and the Sitemap URLs of a robots.txt.
+// Paths are cut at their first wildcard, the root path is skipped.
+func parseRobots(body []byte) (paths []string, sitemaps []string) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "allow", "disallow":
+			if i := strings.IndexAny(value, "*$"); i >= 0 {
+				value = value[:i]
+			}
+			if strings.HasPrefix(value, "/") && value != "/" {
+				paths = append(paths, value)
+			}
+		case "sitemap":
+			if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+				sitemaps = append(sitemaps, value)
+			}
+		}
+	}
+	return paths, sitemaps
+}
+
+// Extract the <loc> URLs of a sitemap or sitemap index.
+func sitemapLocs(body []byte) []string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	var locs []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return locs
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "loc" {
+			continue
+		}
+		var loc string
+		if err := decoder.DecodeElement(&loc, &start); err != nil {
+			return locs
+		}
+		if loc = strings.TrimSpace(loc); loc != "" {
+			locs = append(locs, loc)
+		}
+	}
+}
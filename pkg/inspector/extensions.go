@@ -0,0 +1,185 @@
+package inspector
+
+import (
+	_ "embed"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Default mappings, user files in the same format are merged over them.
+var (
+	//go:embed mappings/extensions.yaml
+	builtinExtensions []byte
+
+	//go:embed mappings/mime.yaml
+	builtinMIMETypes []byte
+)
+
+// BuiltinMappings returns the embedded default mappings of file extensions
+// and content types to suffix labels, in the YAML format expected by the
+// ExtensionsFile and MIMEFile options.
+func BuiltinMappings() (extensions []byte, mimeTypes []byte) {
+	return builtinExtensions, builtinMIMETypes
+}
+
+// ValidateMappingFile parses an extensions file, or a MIME types file when
+// mime is set, and returns its number of entries along with the problems of
+// its keys: extensions not starting with a dot, content types without a slash.
+func ValidateMappingFile(file string, mime bool) (int, []string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, nil, fmt.Errorf("reading mapping file %s: %w", file, err)
+	}
+	entries, err := parseMapping(data, file)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var problems []string
+	for key := range entries {
+		switch {
+		case mime && !strings.Contains(key, "/"):
+			problems = append(problems, fmt.Sprintf("content type %q has no slash", key))
+		case !mime && !strings.HasPrefix(key, "."):
+			problems = append(problems, fmt.Sprintf("extension %q doesn't start with a dot", key))
+		}
+	}
+	sort.Strings(problems)
+	return len(entries), problems, nil
+}
+
+// Suffix labels of file extensions, used in passive mode, and of content types.
+type mappings struct {
+	extensions map[string]string
+	folded     map[string]string // extensions keyed in lower case
+	mimeTypes  map[string]string
+}
+
+// The built-in mappings, parsed once and shared by the runners without
+// mapping files, which only read them.
+var builtinMappings = sync.OnceValues(func() (*mappings, error) {
+	extensions, err := parseMapping(builtinExtensions, "built-in extensions")
+	if err != nil {
+		return nil, err
+	}
+	mimeTypes, err := parseMapping(builtinMIMETypes, "built-in MIME types")
+	if err != nil {
+		return nil, err
+	}
+	return newMappings(extensions, mimeTypes), nil
+})
+
+// Load the default mappings and merge the user extension and MIME files over them.
+func loadMappings(options *Options) (*mappings, error) {
+	builtin, err := builtinMappings()
+	if err != nil {
+		return nil, err
+	}
+	if options.ExtensionsFile == "" && options.MIMEFile == "" {
+		return builtin, nil
+	}
+
+	extensions := maps.Clone(builtin.extensions)
+	mimeTypes := maps.Clone(builtin.mimeTypes)
+	if err := mergeMappingFile(extensions, options.ExtensionsFile); err != nil {
+		return nil, err
+	}
+	if err := mergeMappingFile(mimeTypes, options.MIMEFile); err != nil {
+		return nil, err
+	}
+	return newMappings(extensions, mimeTypes), nil
+}
+
+func newMappings(extensions, mimeTypes map[string]string) *mappings {
+	// Lower case extensions win over the ones differing only by case (.c and .C).
+	folded := make(map[string]string, len(extensions))
+	for ext, label := range extensions {
+		if _, ok := folded[strings.ToLower(ext)]; !ok || ext == strings.ToLower(ext) {
+			folded[strings.ToLower(ext)] = label
+		}
+	}
+	// Content types are matched by their lower case media type, without
+	// parameters, the keys already in this form winning.
+	mediaTypes := make(map[string]string, len(mimeTypes))
+	for contentType, label := range mimeTypes {
+		mediaType, _ := parseContentType(contentType)
+		if _, ok := mediaTypes[mediaType]; !ok || contentType == mediaType {
+			mediaTypes[mediaType] = label
+		}
+	}
+	return &mappings{extensions: extensions, folded: folded, mimeTypes: mediaTypes}
+}
+
+// Merge the entries of a YAML or JSON mapping file, an empty label removes an entry.
+func mergeMappingFile(mapping map[string]string, file string) error {
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("reading mapping file %s: %w", file, err)
+	}
+	entries, err := parseMapping(data, file)
+	if err != nil {
+		return err
+	}
+	for key, label := range entries {
+		if label == "" {
+			delete(mapping, key)
+			continue
+		}
+		mapping[key] = label
+	}
+	return nil
+}
+
+// Parse a mapping, JSON being a subset of YAML both formats are accepted.
+func parseMapping(data []byte, name string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("parsing mapping file %s: %w", name, err)
+	}
+	return mapping, nil
+}
+
+// Return the suffix label for a content type, or an empty string when unknown.
+func (m *mappings) suffixFor(contentType string) string {
+	return m.mimeTypes[contentType]
+}
+
+// Return the label for a URL whose file name ends with one of the passive
+// extensions. The query string is ignored and extensions are matched case
+// insensitively, an exact case match being preferred.
+func (m *mappings) passiveLabel(target string) (string, bool) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "", false
+	}
+	name := path.Base(parsed.Path)
+
+	// Try the longest extension first, from the first dot of the file name.
+	for i := strings.Index(name, "."); i >= 0; {
+		ext := name[i:]
+		if label, ok := m.extensions[ext]; ok {
+			return label, true
+		}
+		if label, ok := m.folded[strings.ToLower(ext)]; ok {
+			return label, true
+		}
+
+		next := strings.Index(name[i+1:], ".")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return "", false
+}
@@ -0,0 +1,90 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// TypeGitExposure marks a confirmed exposed git repository, the Host being
+// the URL of its .git directory.
+const TypeGitExposure = "GIT EXPOSURE"
+
+// GitInfo holds the details of an exposed git repository.
+type GitInfo struct {
+	Branch  string   `json:"branch,omitempty"`
+	Commit  string   `json:"commit,omitempty"` // Checked out commit of a detached HEAD.
+	Remotes []string `json:"remotes,omitempty"`
+}
+
+// A detached HEAD holds the commit hash.
+var gitCommitRegex = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// Check the .git directory of a URL whose path goes through one, fetching
+// its HEAD and config files, and return the exposed repository, nil when the
+// URL has no .git directory, it was already checked or isn't exposed.
+func (r *Runner) checkGit(ctx context.Context, target string) *Result {
+	normalized, err := NormalizeURL(target, r.options.DefaultScheme)
+	if err != nil {
+		return nil
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return nil
+	}
+	i := strings.Index(parsed.Path, "/.git/")
+	if i < 0 {
+		if !strings.HasSuffix(parsed.Path, "/.git") {
+			return nil
+		}
+		i = len(parsed.Path) - len("/.git")
+	}
+	repository := parsed.Scheme + "://" + parsed.Host + parsed.Path[:i] + "/.git/"
+	if _, checked := r.gitChecked.LoadOrStore(repository, true); checked {
+		return nil
+	}
+
+	head := strings.TrimSpace(string(r.fetchListing(ctx, repository+"HEAD")))
+	info := &GitInfo{}
+	switch {
+	case strings.HasPrefix(head, "ref: refs/"):
+		info.Branch = strings.TrimPrefix(strings.TrimPrefix(head, "ref: "), "refs/heads/")
+	case gitCommitRegex.MatchString(head):
+		info.Commit = head
+	default:
+		return nil
+	}
+	// Not every exposed repository serves its config, HEAD is enough to confirm it.
+	if config := r.fetchListing(ctx, repository+"config"); bytes.Contains(config, []byte("[core]")) {
+		info.Remotes = parseGitRemotes(config)
+	}
+
+	result := &Result{Host: repository, Type: TypeGitExposure}
+	result.Data.Git = info
+	return result
+}
+
+// Parse the remotes of a git config as "name url" entries.
+func parseGitRemotes(config []byte) []string {
+	var remotes []string
+	remote := ""
+	scanner := bufio.NewScanner(bytes.NewReader(config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			remote = ""
+			if name, ok := strings.CutPrefix(line, "[remote \""); ok {
+				remote = strings.TrimSuffix(name, "\"]")
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if remote != "" && ok && strings.TrimSpace(key) == "url" {
+			remotes = append(remotes, remote+" "+strings.TrimSpace(value))
+		}
+	}
+	return remotes
+}
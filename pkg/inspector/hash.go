@@ -0,0 +1,106 @@
+package inspector
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Supported body hash algorithms.
+var hashFuncs = map[string]func([]byte) string{
+	"md5": func(body []byte) string {
+		sum := md5.Sum(body)
+		return hex.EncodeToString(sum[:])
+	},
+	"sha1": func(body []byte) string {
+		sum := sha1.Sum(body)
+		return hex.EncodeToString(sum[:])
+	},
+	"sha256": func(body []byte) string {
+		sum := sha256.Sum256(body)
+		return hex.EncodeToString(sum[:])
+	},
+	"mmh3": func(body []byte) string {
+		return strconv.FormatInt(int64(int32(murmur3(body, 0))), 10)
+	},
+}
+
+// Split and validate a comma separated list of hash algorithms.
+func parseHashes(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var algorithms []string
+	for _, algorithm := range strings.Split(value, ",") {
+		algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+		if _, ok := hashFuncs[algorithm]; !ok {
+			return nil, fmt.Errorf("unsupported hash %q, use md5, sha1, sha256 or mmh3", algorithm)
+		}
+		algorithms = append(algorithms, algorithm)
+	}
+	return algorithms, nil
+}
+
+// Compute the digests of the body for each algorithm.
+func hashBody(algorithms []string, body []byte) map[string]string {
+	if len(algorithms) == 0 {
+		return nil
+	}
+	hashes := make(map[string]string, len(algorithms))
+	for _, algorithm := range algorithms {
+		hashes[algorithm] = hashFuncs[algorithm](body)
+	}
+	return hashes
+}
+
+// MurmurHash3 x86 32-bit, as used by Shodan and httpx for body hashes.
+func murmur3(data []byte, seed uint32) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+
+	h := seed
+	nblocks := len(data) / 4
+	for i := 0; i < nblocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	tail := data[nblocks*4:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
@@ -0,0 +1,120 @@
+package inspector
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse "Name: value" header lines from the options and the header file.
+func parseHeaders(options *Options) (http.Header, error) {
+	lines := append([]string{}, options.Headers...)
+
+	if options.HeaderFile != "" {
+		file, err := os.Open(options.HeaderFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening header file %s: %w", options.HeaderFile, err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				lines = append(lines, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading header file %s: %w", options.HeaderFile, err)
+		}
+	}
+
+	headers := make(http.Header)
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", line)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// Apply the custom headers to a request, the Host header sets the request host.
+func setHeaders(req *http.Request, headers http.Header) {
+	for name, values := range headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = values[0]
+			continue
+		}
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+}
+
+// Headers applied to the requests of the hosts matching a pattern.
+type hostHeaderRule struct {
+	pattern string
+	headers http.Header
+}
+
+// Load the host headers file, a YAML map of host patterns to "Name: value"
+// header maps. Patterns use path.Match syntax (*.example.com) and match the
+// hostname, or the host and port when they include a port. Rules are sorted
+// from the least to the most specific, the longest pattern winning.
+func loadHostHeaders(options *Options) ([]hostHeaderRule, error) {
+	if options.HostHeadersFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(options.HostHeadersFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading host headers file %s: %w", options.HostHeadersFile, err)
+	}
+	patterns := make(map[string]map[string]string)
+	if err := yaml.Unmarshal(data, &patterns); err != nil {
+		return nil, fmt.Errorf("parsing host headers file %s: %w", options.HostHeadersFile, err)
+	}
+
+	var rules []hostHeaderRule
+	for pattern, values := range patterns {
+		pattern = strings.ToLower(pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid host pattern %q: %w", pattern, err)
+		}
+		headers := make(http.Header)
+		for name, value := range values {
+			headers.Set(name, value)
+		}
+		rules = append(rules, hostHeaderRule{pattern: pattern, headers: headers})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].pattern) != len(rules[j].pattern) {
+			return len(rules[i].pattern) < len(rules[j].pattern)
+		}
+		return rules[i].pattern < rules[j].pattern
+	})
+	return rules, nil
+}
+
+// Apply the headers of the rules matching the request host, overriding the
+// custom headers.
+func setHostHeaders(req *http.Request, rules []hostHeaderRule) {
+	for _, rule := range rules {
+		host := strings.ToLower(req.URL.Hostname())
+		if strings.Contains(rule.pattern, ":") {
+			host = strings.ToLower(req.URL.Host)
+		}
+		if matched, _ := path.Match(rule.pattern, host); matched {
+			setHeaders(req, rule.headers)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package inspector
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Semaphores created lazily for each host to cap simultaneous connections.
+type hostSemaphore struct {
+	mu   sync.Mutex
+	size int
+	sems map[string]chan struct{}
+}
+
+// Create a per-host semaphore allowing size concurrent requests, nil when unlimited.
+func newHostSemaphore(size int) *hostSemaphore {
+	if size <= 0 {
+		return nil
+	}
+	return &hostSemaphore{size: size, sems: make(map[string]chan struct{})}
+}
+
+// Return the semaphore of host, creating it on first use.
+func (h *hostSemaphore) get(host string) chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sem, ok := h.sems[host]
+	if !ok {
+		sem = make(chan struct{}, h.size)
+		h.sems[host] = sem
+	}
+	return sem
+}
+
+// Acquire a spot for host and return the function releasing it, or an error
+// when the context is done first.
+func (h *hostSemaphore) Acquire(ctx context.Context, host string) (func(), error) {
+	if h == nil {
+		return func() {}, nil
+	}
+	sem := h.get(host)
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Return the hostname of a target URL, or the target itself when it can't be parsed.
+func hostOf(target string) string {
+	raw := target
+	if !strings.Contains(raw, "://") {
+		raw = "//" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return target
+	}
+	return u.Hostname()
+}
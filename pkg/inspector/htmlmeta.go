@@ -0,0 +1,64 @@
+package inspector
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// HTMLMeta holds the meta details of an HTML page.
+type HTMLMeta struct {
+	Generator string `json:"generator,omitempty"`
+	Canonical string `json:"canonical,omitempty"`
+	Robots    string `json:"robots,omitempty"`
+	OGTitle   string `json:"og_title,omitempty"`
+}
+
+var (
+	metaTagRegex   = regexp.MustCompile(`(?is)<(meta|link)\s[^>]*>`)
+	attributeRegex = regexp.MustCompile(`(?i)([\w:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// Extract the generator, canonical URL, robots directives and og:title of
+// an HTML page, nil when it has none. The canonical URL is resolved against
+// the page URL, the first of each tag wins.
+func extractHTMLMeta(base *url.URL, body []byte) *HTMLMeta {
+	meta := &HTMLMeta{}
+	for _, tag := range metaTagRegex.FindAllSubmatch(body, -1) {
+		attributes := make(map[string]string)
+		for _, match := range attributeRegex.FindAllSubmatch(tag[0], -1) {
+			value := string(match[2]) + string(match[3]) + string(match[4])
+			attributes[strings.ToLower(string(match[1]))] = strings.TrimSpace(html.UnescapeString(value))
+		}
+
+		if strings.EqualFold(string(tag[1]), "link") {
+			if meta.Canonical == "" && strings.EqualFold(attributes["rel"], "canonical") && attributes["href"] != "" {
+				meta.Canonical = attributes["href"]
+				if canonical, err := base.Parse(attributes["href"]); err == nil {
+					meta.Canonical = canonical.String()
+				}
+			}
+			continue
+		}
+		content := attributes["content"]
+		var field *string
+		switch name := strings.ToLower(attributes["name"] + attributes["property"]); name {
+		case "generator":
+			field = &meta.Generator
+		case "robots":
+			field = &meta.Robots
+		case "og:title":
+			field = &meta.OGTitle
+		default:
+			continue
+		}
+		if *field == "" {
+			*field = content
+		}
+	}
+	if *meta == (HTMLMeta{}) {
+		return nil
+	}
+	return meta
+}
@@ -0,0 +1,1111 @@
+// Package inspector analyzes URLs to retrieve HTTP status codes, content
+// lengths, content types and file suffixes.
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Runner inspects URLs with a shared HTTP client.
+type Runner struct {
+	options     *Options
+	client      *http.Client
+	headers     http.Header
+	hostHeaders []hostHeaderRule
+	scope       *scope
+	safety      *safetyFilter
+	matchRegex  []*regexp.Regexp
+	filterRegex []*regexp.Regexp
+	hashes      []string
+	ranges      *rangeMatchers
+	newerThan   time.Time // Last-Modified cutoffs of the age filters, zero for none.
+	olderThan   time.Time
+	validators  map[string]Validator // Validators of the URLs requested conditionally.
+	limiter     *limiter
+	hostLimiter *hostLimiter
+	hostSem     *hostSemaphore
+	throttle    *hostThrottle
+	pause       pauseGate
+	cdn         *cdnDetector
+	dns         *dnsResolver
+	soft404     soft404Calibrator
+	mappings    *mappings
+	severities  map[string]string // Severities of the lower case suffix labels.
+	methods     []string
+	ports       []portProbe
+	paths       []string
+	userAgents  []string // Rotated across the requests, nil to send UserAgent.
+	encodings   string   // Accept-Encoding header, empty for the transparent gzip.
+	words       []string
+	browser     string // Chrome binary taking screenshots, empty when disabled.
+	cookies     *cookieSource
+	body        []byte
+	secretRules []secretRule
+	jsonPaths   []jsonPath
+	pathRules   []pathRule
+	processed   atomic.Int64
+	discovered  atomic.Int64 // URLs inspected after being discovered or generated from input URLs.
+	expanded    sync.Map     // Origins whose robots.txt and sitemaps were fetched.
+	gitChecked  sync.Map     // .git directories whose exposure was checked.
+	redirects   sync.Map     // Paths and parameters checked for open redirects.
+	stats       statsCollector
+}
+
+// New creates a Runner from the given options.
+func New(options *Options) (*Runner, error) {
+	switch strings.ToUpper(options.Method) {
+	case "HEAD", "GET", "AUTO":
+	default:
+		return nil, fmt.Errorf("invalid method %s, use HEAD, GET or auto", options.Method)
+	}
+	if options.DefaultScheme == "" {
+		options.DefaultScheme = "https"
+	}
+	if options.DefaultScheme != "http" && options.DefaultScheme != "https" {
+		return nil, fmt.Errorf("invalid default scheme %s, use http or https", options.DefaultScheme)
+	}
+	if options.Unique != "" && options.Unique != "host" && options.Unique != "hash" {
+		return nil, fmt.Errorf("invalid unique mode %s, use host or hash", options.Unique)
+	}
+	if options.MinSeverity != "" && severityRank(options.MinSeverity) == 0 {
+		return nil, fmt.Errorf("invalid minimum severity %s, use %s", options.MinSeverity, strings.Join(severityLevels, ", "))
+	}
+	if options.Threads <= 0 {
+		options.Threads = 1
+	}
+	if options.SniffSize <= 0 {
+		options.SniffSize = 512
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = 10 * 1024 * 1024
+	}
+	if options.MaxRedirects <= 0 {
+		options.MaxRedirects = 10
+	}
+	if options.CrawlDepth <= 0 {
+		options.CrawlDepth = 2
+	}
+	if options.CrawlBudget <= 0 {
+		options.CrawlBudget = 100
+	}
+
+	proxy, err := proxyFunc(options)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := parseHeaders(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.HostHeader != "" {
+		headers.Set("Host", options.HostHeader)
+	}
+	hostHeaders, err := loadHostHeaders(options)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := newScope(options)
+	if err != nil {
+		return nil, err
+	}
+	safety, err := newSafetyFilter(options)
+	if err != nil {
+		return nil, err
+	}
+
+	matchRegex, err := compileRegexes(options.MatchRegex)
+	if err != nil {
+		return nil, err
+	}
+	filterRegex, err := compileRegexes(options.FilterRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := parseHashes(options.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := parseRangeMatchers(options)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	newerThan, err := parseCutoff(options.NewerThan, now)
+	if err != nil {
+		return nil, err
+	}
+	olderThan, err := parseCutoff(options.OlderThan, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateErrorTypes(options.MatchError); err != nil {
+		return nil, err
+	}
+	if err := validateErrorTypes(options.FilterError); err != nil {
+		return nil, err
+	}
+
+	resolver, err := newDNSResolver(options)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, err := loadMappings(options)
+	if err != nil {
+		return nil, err
+	}
+
+	severities, err := loadSeverities(options)
+	if err != nil {
+		return nil, err
+	}
+	pathRules, err := loadPathRules(options)
+	if err != nil {
+		return nil, err
+	}
+	secretRules, err := loadSecretRules(options)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := parseMethods(options.RequestMethods)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := parsePorts(options.Ports)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := loadPaths(options)
+	if err != nil {
+		return nil, err
+	}
+	options.UserAgent = resolveUserAgent(options.UserAgent)
+	userAgents, err := loadUserAgents(options)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	if options.Wordlist != "" {
+		if words, err = readWordlist(options.Wordlist, "wordlist"); err != nil {
+			return nil, err
+		}
+	}
+	body, err := requestBody(options)
+	if err != nil {
+		return nil, err
+	}
+	encodings, err := acceptEncoding(options)
+	if err != nil {
+		return nil, err
+	}
+	jsonPaths, err := parseJSONPaths(options.ExtractJSON)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil && len(methods) == 0 {
+		return nil, fmt.Errorf("a request body is only sent with explicit request methods")
+	}
+
+	cookies, err := loadCookies(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var browser string
+	if options.ScreenshotDir != "" {
+		if browser, err = setupScreenshots(options); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.BackupGen && options.Passive {
+		return nil, fmt.Errorf("backup generation requests every variant and can't be used in passive mode")
+	}
+
+	if options.HTTP2 && options.ForceHTTP1 {
+		return nil, fmt.Errorf("HTTP/2 and forced HTTP/1.1 can't be enabled together")
+	}
+	if options.ConnectTimeout < 0 || options.TLSTimeout < 0 || options.ResponseHeaderTimeout < 0 {
+		return nil, fmt.Errorf("timeouts can't be negative")
+	}
+	if options.IPv4Only && options.IPv6Only {
+		return nil, fmt.Errorf("IPv4 only and IPv6 only can't be enabled together")
+	}
+
+	if options.MaxIdleConnsPerHost <= 0 {
+		options.MaxIdleConnsPerHost = options.Threads
+	}
+
+	tlsConfig, err := buildTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// The connect and TLS handshake timeouts default to the overall timeout.
+	timeout := time.Duration(options.Timeout) * time.Second
+	connectTimeout, tlsTimeout := timeout, timeout
+	if options.ConnectTimeout > 0 {
+		connectTimeout = options.ConnectTimeout
+	}
+	if options.TLSTimeout > 0 {
+		tlsTimeout = options.TLSTimeout
+	}
+
+	// A single transport is shared by all workers so connections are reused.
+	dialer := &net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+		Resolver:  resolver.resolver,
+	}
+	sourceIPs, err := loadSourceIPs(options)
+	if err != nil {
+		return nil, err
+	}
+	// Connections are bound to the source IPs in turn.
+	var nextSource atomic.Uint64
+	bind := func(d *net.Dialer) func(context.Context, string, string) (net.Conn, error) {
+		if len(sourceIPs) == 0 {
+			return d.DialContext
+		}
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			bound := *d
+			bound.LocalAddr = &net.TCPAddr{IP: sourceIPs[(nextSource.Add(1)-1)%uint64(len(sourceIPs))]}
+			return bound.DialContext(ctx, network, addr)
+		}
+	}
+	dialContext := bind(dialer)
+	if safety != nil {
+		// Connections are checked once resolved, proxies excepted.
+		checked := *dialer
+		checked.Control = safety.control
+		dialTrusted, dialChecked := dialContext, bind(&checked)
+		dialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if safety.trusted[addr] {
+				return dialTrusted(ctx, network, addr)
+			}
+			return dialChecked(ctx, network, addr)
+		}
+	}
+	// Forced address families only dial the addresses of their family.
+	if options.IPv4Only || options.IPv6Only {
+		family := "4"
+		if options.IPv6Only {
+			family = "6"
+		}
+		dial := dialContext
+		dialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if network == "tcp" {
+				network += family
+			}
+			return dial(ctx, network, addr)
+		}
+	}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialContext,
+		MaxIdleConns:          options.Threads * 2,
+		MaxIdleConnsPerHost:   options.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   tlsTimeout,
+		ResponseHeaderTimeout: options.ResponseHeaderTimeout, // Zero for none, within the overall timeout.
+		DisableKeepAlives:     options.DisableKeepAlive,
+		TLSClientConfig:       tlsConfig,
+		// A custom TLS config disables HTTP/2 unless it is explicitly attempted.
+		ForceAttemptHTTP2: options.HTTP2,
+	}
+	if options.ForceHTTP1 {
+		// A non-nil empty map prevents the HTTP/2 upgrade through ALPN.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	authTransport, err := newAuthTransport(transport, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a custom HTTP client with the specified timeout, redirect and transport settings.
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			// Stop at the redirect response itself when not following or past the limit.
+			if !options.FollowRedirects || len(via) > options.MaxRedirects || !followsRedirects(req) {
+				return http.ErrUseLastResponse
+			}
+			// Redirects to blocked hosts are not followed.
+			if safety != nil && !safety.allowsHost(req.URL.String(), options.DefaultScheme) {
+				return http.ErrUseLastResponse
+			}
+			setHostHeaders(req, hostHeaders)
+			return nil
+		},
+		Transport: authTransport,
+	}
+
+	return &Runner{
+		options:     options,
+		client:      client,
+		headers:     headers,
+		hostHeaders: hostHeaders,
+		scope:       scope,
+		safety:      safety,
+		matchRegex:  matchRegex,
+		filterRegex: filterRegex,
+		hashes:      hashes,
+		ranges:      ranges,
+		newerThan:   newerThan,
+		olderThan:   olderThan,
+		limiter:     newLimiter(options.RateLimit),
+		hostLimiter: newHostLimiter(options.RateLimitPerHost),
+		hostSem:     newHostSemaphore(options.HostConcurrency),
+		throttle:    newHostThrottle(options.AutoThrottle),
+		cdn:         &cdnDetector{resolver: resolver.resolver},
+		dns:         resolver,
+		mappings:    mappings,
+		severities:  severities,
+		secretRules: secretRules,
+		jsonPaths:   jsonPaths,
+		pathRules:   pathRules,
+		methods:     methods,
+		ports:       ports,
+		paths:       paths,
+		userAgents:  userAgents,
+		encodings:   encodings,
+		words:       words,
+		browser:     browser,
+		cookies:     cookies,
+		body:        body,
+	}, nil
+}
+
+// Build the transport proxy function. The HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables are used when no proxy is configured.
+func proxyFunc(options *Options) (func(*http.Request) (*url.URL, error), error) {
+	if options.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(options.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %s: %w", options.Proxy, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, use http, https or socks5", proxyURL.Scheme)
+	}
+
+	if options.ProxyAuth != "" {
+		username, password, _ := strings.Cut(options.ProxyAuth, ":")
+		proxyURL.User = url.UserPassword(username, password)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// Run inspects every URL received from targets concurrently and sends the
+// results passing the matchers, along with the failed inspections passing
+// the error matchers, to the returned channel. The channel is closed once
+// targets is drained, or once ctx is cancelled and the in-flight
+// inspections have finished. When filtering duplicates per host, results
+// are sent once all are inspected.
+func (r *Runner) Run(ctx context.Context, targets <-chan string) <-chan *Result {
+	requests := make(chan Request)
+	go func() {
+		defer close(requests)
+		for target := range targets {
+			select {
+			case requests <- Request{URL: target}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return r.RunRequests(ctx, requests)
+}
+
+// RunRequests is like Run for URLs sent with their own method, headers and body.
+func (r *Runner) RunRequests(ctx context.Context, requests <-chan Request) <-chan *Result {
+	results := make(chan *Result)
+	r.stats.begin()
+
+	output := results
+	if r.options.FilterDuplicatesPerHost {
+		collapsed := make(chan *Result)
+		go collapseDuplicates(results, collapsed, r.options.Unique == "hash")
+		output = collapsed
+	}
+	if r.options.Unique != "" {
+		unique := make(chan *Result)
+		go uniqueResults(output, unique, r.options.Unique)
+		output = unique
+	}
+
+	go func() {
+		defer close(results)
+
+		// Set up a WaitGroup and a semaphore (channel) to control concurrency
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, r.options.Threads)
+
+		defer wg.Wait()
+
+		// To preserve the input order, the results of each input URL and of
+		// the URLs derived from it are collected apart, then sent input after
+		// input. Input URLs finishing early wait for the previous ones, up to
+		// a window of inputs bounding the buffered results.
+		var sequence chan chan []*Result
+		if r.options.PreserveOrder {
+			sequence = make(chan chan []*Result, 2*r.options.Threads)
+			sequenced := make(chan struct{})
+			go func() {
+				defer close(sequenced)
+				for collected := range sequence {
+					for _, result := range <-collected {
+						results <- result
+					}
+				}
+			}()
+			defer func() {
+				close(sequence)
+				<-sequenced
+			}()
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case request, ok := <-requests:
+				if !ok {
+					return
+				}
+				j := inputJob(request)
+				if sequence != nil {
+					sequence <- r.processOrdered(ctx, j, sem)
+					continue
+				}
+				wg.Add(1)
+				go r.processURL(ctx, j, &wg, sem, results)
+			}
+		}
+	}()
+
+	return output
+}
+
+// Inspect an input URL and the URLs derived from it, collecting their
+// results to be sent once they are all inspected.
+func (r *Runner) processOrdered(ctx context.Context, j job, sem chan struct{}) chan []*Result {
+	var wg sync.WaitGroup
+	own := make(chan *Result)
+	collected := make(chan []*Result, 1)
+	go func() {
+		var buffered []*Result
+		for result := range own {
+			buffered = append(buffered, result)
+		}
+		collected <- buffered
+	}()
+
+	wg.Add(1)
+	go r.processURL(ctx, j, &wg, sem, own)
+	go func() {
+		wg.Wait()
+		close(own)
+	}()
+	return collected
+}
+
+// Processed returns the number of URLs whose inspection has completed.
+func (r *Runner) Processed() int64 {
+	return r.processed.Load()
+}
+
+// Stats returns the statistics of the URLs inspected so far by Run.
+func (r *Runner) Stats() Stats {
+	return r.stats.snapshot(r.processed.Load() + r.discovered.Load())
+}
+
+// A URL to inspect, from the input or discovered while inspecting another.
+type job struct {
+	target    string
+	source    string   // Robots.txt, sitemap or page listing a discovered URL.
+	generated bool     // URL of a template, or input URL with a path of the wordlist appended.
+	depth     int      // Number of links followed from the input URL.
+	crawl     *crawl   // Crawl of the input URL, nil when not crawling.
+	request   *Request // Custom request of an input URL, nil for a plain one.
+}
+
+// Build the job of an input request, only keeping it when customized.
+func inputJob(request Request) job {
+	j := job{target: request.URL}
+	if request.Method != "" || len(request.Headers) > 0 || request.Body != nil {
+		j.request = &request
+	}
+	return j
+}
+
+// Inspect an input, discovered or generated URL and send its results.
+func (r *Runner) processURL(ctx context.Context, j job, wg *sync.WaitGroup, sem chan struct{}, results chan<- *Result) {
+	defer wg.Done()
+	target, source := j.target, j.source
+	input := source == "" && !j.generated
+	// Only input URLs count as processed, discovered ones are counted apart.
+	completed := &r.processed
+	if !input {
+		completed = &r.discovered
+	}
+
+	// Templates are only expanded, each of their URLs being inspected concurrently.
+	if input && isTemplate(target) {
+		err := expandTemplate(target, r.words, func(generated string) {
+			wg.Add(1)
+			go r.processURL(ctx, job{target: generated, generated: true}, wg, sem, results)
+		})
+		if err != nil {
+			result := &Result{Host: target, Err: err}
+			r.stats.record(result, false)
+			r.options.Metrics.record(result, false)
+			if r.MatchError(err) {
+				results <- result
+			}
+		}
+		completed.Add(1)
+		return
+	}
+
+	// Out of scope and blocked URLs are skipped before any request.
+	if (r.scope != nil && !r.scope.allows(target, r.options.DefaultScheme)) ||
+		(r.safety != nil && !r.safety.allowsHost(target, r.options.DefaultScheme)) {
+		r.stats.skip()
+		completed.Add(1)
+		return
+	}
+
+	// Acquire a spot for the host first, so waiting on a busy host doesn't hold a thread
+	dequeue := r.options.Metrics.enqueue()
+	defer dequeue()
+	release, err := r.hostSem.Acquire(ctx, hostOf(target))
+	if err != nil {
+		return
+	}
+	defer release()
+
+	// Acquire a spot in the semaphore, unless the run is cancelled meanwhile
+	select {
+	case sem <- struct{}{}:
+		dequeue()
+	case <-ctx.Done():
+		return
+	}
+
+	defer func() {
+		// Release the spot in the semaphore when done
+		<-sem
+	}()
+
+	candidates := []string{target}
+	if len(r.ports) > 0 {
+		candidates = portVariants(target, r.ports)
+	}
+	// Each path of the wordlist is inspected concurrently with the input URL.
+	if input && len(r.paths) > 0 {
+		for _, base := range candidates {
+			for _, path := range r.paths {
+				wg.Add(1)
+				go r.processURL(ctx, job{target: joinPath(base, path), generated: true}, wg, sem, results)
+			}
+		}
+	}
+	if r.options.BackupGen {
+		var variants []string
+		for _, candidate := range candidates {
+			variants = append(variants, backupVariants(candidate, r.options.DefaultScheme)...)
+		}
+		candidates = variants
+	}
+	var crawledLinks []string
+	for _, candidate := range candidates {
+		probed := r.probe(withRequest(ctx, j.request), candidate)
+		if ctx.Err() != nil {
+			return // Interrupted inspections are neither completed nor reported.
+		}
+		for _, result := range probed {
+			if source != "" {
+				result.Data.Source = source
+			}
+			crawledLinks = append(crawledLinks, result.links...)
+			matched := result.Err == nil && r.Match(result)
+			if matched && r.browser != "" && !result.Data.NotModified && isHTML(result) {
+				result.Data.Screenshot = r.screenshot(ctx, result.Host)
+			}
+			r.stats.record(result, matched)
+			r.options.Metrics.record(result, matched)
+			if matched || (result.Err != nil && r.MatchError(result.Err)) {
+				results <- result
+			}
+			if matched {
+				for _, child := range result.Children {
+					results <- child
+				}
+			}
+		}
+	}
+	// Confirm the exposure of the git repository of .git URLs.
+	if r.options.GitCheck {
+		if result := r.checkGit(ctx, target); result != nil {
+			results <- result
+		}
+	}
+	// Inject a canary domain in the redirect parameters of the URL.
+	if r.options.OpenRedirect {
+		for _, result := range r.checkOpenRedirect(ctx, target) {
+			results <- result
+		}
+	}
+	// Inspect the URLs of the robots.txt and sitemaps, one level deep.
+	if r.options.Expand && input {
+		for _, discovered := range r.expand(ctx, target) {
+			wg.Add(1)
+			go r.processURL(ctx, job{target: discovered.target, source: discovered.source}, wg, sem, results)
+		}
+	}
+	if r.options.Crawl {
+		if j.crawl == nil && input {
+			j.crawl = newCrawl(target, r.options)
+		}
+		if j.crawl != nil {
+			for _, link := range j.crawl.follow(r, j.depth, crawledLinks) {
+				wg.Add(1)
+				go r.processURL(ctx, job{target: link, source: target, depth: j.depth + 1, crawl: j.crawl}, wg, sem, results)
+			}
+		}
+	}
+	completed.Add(1)
+
+	// Apply delay between requests
+	if r.options.Delay > 0 {
+		select {
+		case <-time.After(r.options.Delay):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// Inspect a target, trying https and then http for scheme-less input when
+// probing all schemes. Failed inspections are returned with Err set.
+func (r *Runner) probe(ctx context.Context, target string) []*Result {
+	candidates := []string{target}
+	if r.options.ProbeAllSchemes && !strings.Contains(target, "://") {
+		candidates = []string{"https://" + target, "http://" + target}
+	}
+
+	// One result per request method, the default method being used when none are set.
+	methods := r.methods
+	if request := requestOf(ctx); request != nil && request.method() != "" {
+		methods = []string{request.method()}
+	}
+	if len(methods) == 0 {
+		methods = []string{""}
+	}
+
+	var results []*Result
+	var lastErr error
+	for _, candidate := range candidates {
+		var candidateResults []*Result
+		for _, method := range methods {
+			result, err := r.inspect(ctx, candidate, method)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			candidateResults = append(candidateResults, result)
+		}
+		if len(candidateResults) == 0 {
+			continue
+		}
+
+		if r.options.AllowedMethods && candidateResults[0].Type == TypeRequest {
+			allowed := r.allowedMethods(ctx, candidateResults[0].Host)
+			for _, result := range candidateResults {
+				result.Data.AllowedMethods = allowed
+			}
+		}
+		results = append(results, candidateResults...)
+		if !r.options.AllSchemes {
+			break
+		}
+	}
+	if len(results) == 0 {
+		return []*Result{{Host: target, Err: lastErr}}
+	}
+	return results
+}
+
+// Inspect checks a single URL. In passive mode URLs ending with a known
+// extension are labelled without sending any request. Invalid URLs return an
+// error wrapping ErrInvalidURL. Only the first of the request methods is used.
+func (r *Runner) Inspect(ctx context.Context, target string) (*Result, error) {
+	var method string
+	if len(r.methods) > 0 {
+		method = r.methods[0]
+	}
+	result, err := r.inspect(ctx, target, method)
+	if err == nil && r.options.AllowedMethods && result.Type == TypeRequest {
+		result.Data.AllowedMethods = r.allowedMethods(ctx, result.Host)
+	}
+	return result, err
+}
+
+// Inspect a URL with the given request method, or with the configured
+// HEAD, GET or auto method when empty.
+func (r *Runner) inspect(ctx context.Context, target string, requestMethod string) (*Result, error) {
+	target, err := NormalizeURL(target, r.options.DefaultScheme)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip requests based on file extensions and path rules when passive mode is enabled.
+	if r.options.Passive {
+		label, ok := r.mappings.passiveLabel(target)
+		rules := matchPathRules(r.pathRules, target)
+		if ok || len(rules) > 0 {
+			result := &Result{Host: target, Type: TypeExtension}
+			result.Data.Suffix = label
+			result.Data.Severity = r.severityOf(label)
+			result.Data.Rules = rules
+			return result, nil
+		}
+	}
+
+	// Resolve the host first so unresolvable hosts don't cost a request.
+	var dnsInfo *DNSInfo
+	if r.options.DNSDetails {
+		parsed, _ := url.Parse(target)
+		if dnsInfo, err = r.dns.lookup(ctx, parsed.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Perform the HTTP request, HEAD is used unless GET is forced.
+	method := strings.ToUpper(r.options.Method)
+	if method == "AUTO" {
+		method = "HEAD"
+	}
+	var body []byte
+	if requestMethod != "" {
+		method = requestMethod
+		if method != "HEAD" {
+			body = r.body
+			if request := requestOf(ctx); request != nil && request.Body != nil {
+				body = request.Body
+			}
+		}
+	}
+	var conditional http.Header
+	if requestMethod == "" {
+		conditional = r.conditionalHeaders(target)
+	}
+	resp, trace, err := r.tracedRequest(ctx, method, target, conditional, body)
+
+	// Retry with GET when the server rejects or fails the HEAD request in auto mode.
+	if requestMethod == "" && strings.EqualFold(r.options.Method, "auto") && (err != nil || resp.StatusCode >= 400) {
+		getResp, getTrace, getErr := r.tracedRequest(ctx, "GET", target, conditional, nil)
+		if getErr == nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			resp, trace, err = getResp, getTrace, nil
+		}
+	}
+	if isUnresolvable(err) {
+		return nil, fmt.Errorf("%w: %v", ErrUnresolvable, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Only the headers are used, the body is discarded unread unless needed.
+	defer resp.Body.Close()
+
+	// Extract response details.
+	contentType, charset := parseContentType(resp.Header.Get("Content-Type"))
+
+	result := &Result{Host: target, Type: TypeRequest, Header: resp.Header}
+	result.Data.Method = requestMethod
+	result.Data.StatusCode = int64(resp.StatusCode)
+	result.Data.ContentLength = resp.ContentLength
+	result.Data.ContentType = contentType
+	result.Data.Charset = charset
+	result.Data.ContentEncoding = responseEncoding(resp)
+	result.Data.LastModified = lastModified(resp.Header)
+	result.Data.ETag = resp.Header.Get("ETag")
+	result.Data.ResponseTime = trace.elapsed.Milliseconds()
+	result.Data.Proto = resp.Proto
+	// Nothing else to learn from a URL unchanged since the validated response,
+	// reported as it was then.
+	if conditional != nil && resp.StatusCode == http.StatusNotModified {
+		if cached := r.validators[target].Data; cached.StatusCode != 0 {
+			cached.ResponseTime, cached.Proto = result.Data.ResponseTime, result.Data.Proto
+			result.Data = cached
+		}
+		result.Data.NotModified = true
+		return result, nil
+	}
+	// Behind a proxy this is the address of the proxy.
+	if addr, ok := trace.remoteAddr.(*net.TCPAddr); ok {
+		if r.options.RemoteAddr {
+			result.Data.IP = addr.IP.String()
+			result.Data.Port = addr.Port
+		}
+		if r.options.RemoteAddr || r.options.IPv4Only || r.options.IPv6Only {
+			result.Data.IPFamily = ipFamily(addr.IP)
+		}
+	}
+	result.Data.Suffix = r.mappings.suffixFor(contentType)
+	result.Data.Severity = r.severityOf(result.Data.Suffix)
+	result.Data.Rules = matchPathRules(r.pathRules, target)
+	result.Data.RedirectChain = redirectChain(resp)
+	if len(result.Data.RedirectChain) > 0 {
+		result.Data.FinalURL = resp.Request.URL.String()
+	}
+	result.Data.DNS = dnsInfo
+
+	// Download the body when one of the enabled features needs it.
+	analyzeJS := r.options.JSAnalyze && isJavaScript(result)
+	extractJSON := len(r.jsonPaths) > 0 && isJSON(result)
+	crawlHTML := r.options.Crawl && isHTML(result)
+	extractMeta := r.options.HTMLMeta && isHTML(result)
+	listLinks := r.options.ExtractLinks && isHTML(result)
+	full := r.readsFullBody() || analyzeJS || crawlHTML || extractJSON || extractMeta || listLinks
+	if r.options.Sniff || full {
+		result.Body = r.readBody(ctx, resp, target, full, &result.Data)
+	}
+	if r.options.ReadBody {
+		result.Data.BodySize = int64(len(result.Body))
+	}
+	if r.readsFullBody() {
+		result.Data.Words, result.Data.Lines = countWordsLines(result.Body)
+	}
+	if charset == "" && isTextType(contentType) {
+		result.Data.Charset = detectCharset(result.Body)
+	}
+
+	// Detect the real type from the first bytes of the body.
+	if r.options.Sniff {
+		prefix := result.Body[:min(len(result.Body), r.options.SniffSize)]
+		detected, magic := sniffContentType(prefix)
+		result.Data.DetectedType = detected
+		result.Data.TypeMismatch = typeMismatch(detected, magic, contentType)
+	}
+
+	// Compare the response with the one of a nonexistent path on the same host.
+	if r.options.Soft404 || r.options.FilterSoft404 {
+		baseline := r.soft404Baseline(ctx, resp.Request.URL)
+		result.Data.Soft404 = baseline.matches(resp.StatusCode, result.Data.Words, htmlTitle(result.Body))
+	}
+
+	if r.options.RangeProbe || r.options.MatchRangeSupport {
+		supported := r.rangeSupported(ctx, resp.Request.URL.String(), resp)
+		result.Data.RangeSupport = &supported
+	}
+
+	// List the files of ZIP archives without downloading them.
+	if r.options.ZipPeek && isZip(result) && (result.Data.RangeSupport == nil || *result.Data.RangeSupport) {
+		names, count, err := r.peekZip(ctx, resp.Request.URL.String(), resp.ContentLength)
+		if err == nil {
+			result.Data.ZipEntries, result.Data.ZipEntryCount = names, count
+		}
+	}
+
+	// Keep the snippets matched by the body regexes.
+	result.Data.RegexMatches = regexSnippets(r.matchRegex, result.Body)
+
+	if r.options.TechDetect {
+		result.Data.Technologies = detectTechnologies(resp.Header, result.Body)
+	}
+	if r.options.CDNDetect || r.options.FilterCDN {
+		// The final URL is used, a redirect may lead to the CDN.
+		result.Data.CDN = r.cdn.detect(ctx, resp.Request.URL.Hostname(), resp.Header)
+	}
+	if r.options.TLSProbe {
+		result.Data.TLS = tlsInfo(resp.TLS)
+	}
+	if r.options.SecurityHeaders {
+		result.Data.SecurityHeaders = auditSecurityHeaders(resp.Header, resp.TLS != nil)
+	}
+	result.Data.Hashes = hashBody(r.hashes, result.Body)
+	if r.options.Secrets {
+		result.Data.Secrets = findSecrets(r.secretRules, result.Body)
+	}
+	if extractJSON {
+		result.Data.JSONFields = extractJSONFields(r.jsonPaths, result.Body)
+	}
+	if analyzeJS {
+		result.Children = analyzeJavaScript(result.Host, result.Body, r.secretRules)
+	}
+	if extractMeta {
+		result.Data.Meta = extractHTMLMeta(resp.Request.URL, result.Body)
+	}
+	if crawlHTML || listLinks {
+		// Links are relative to the final URL of redirected pages.
+		result.links = extractLinks(resp.Request.URL, result.Body)
+	}
+	if listLinks {
+		result.Data.Links = result.links
+	}
+
+	if r.options.IncludeHeaders {
+		result.Data.Headers = make(map[string]string, len(resp.Header))
+		for name, values := range resp.Header {
+			result.Data.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+	return result, nil
+}
+
+// Report whether the whole body, bounded by MaxBodySize, has to be downloaded.
+func (r *Runner) readsFullBody() bool {
+	options := r.options
+	return options.ReadBody || options.TechDetect || options.Secrets || options.Soft404 || options.FilterSoft404 || options.FilterDuplicatesPerHost || options.Unique == "hash" || len(r.hashes) > 0 || len(r.matchRegex) > 0 || len(r.filterRegex) > 0 ||
+		options.MatchWords != "" || options.MatchLines != "" || options.FilterWords != "" || options.FilterLines != ""
+}
+
+// Walk back through the responses that led to resp and return the followed
+// redirects in request order.
+func redirectChain(resp *http.Response) []Redirect {
+	var chain []Redirect
+	for req := resp.Request; req.Response != nil; req = req.Response.Request {
+		hop := Redirect{URL: req.Response.Request.URL.String(), StatusCode: int64(req.Response.StatusCode)}
+		chain = append([]Redirect{hop}, chain...)
+	}
+	return chain
+}
+
+// Read the whole body up to MaxBodySize bytes, or only the SniffSize bytes
+// when just sniffing, fetching it with GET when the response came from a HEAD
+// request. The body is decompressed and its compression recorded in data.
+func (r *Runner) readBody(ctx context.Context, resp *http.Response, target string, full bool, data *Data) []byte {
+	if resp.Request.Method == "HEAD" {
+		getResp, err := r.doRequest(ctx, "GET", target)
+		if err != nil {
+			return nil
+		}
+		defer getResp.Body.Close()
+		resp = getResp
+	}
+
+	limit := r.options.SniffSize
+	if full && r.options.Sniff {
+		limit = max(r.options.MaxBodySize, limit)
+	} else if full {
+		limit = r.options.MaxBodySize
+	}
+	return readDecoded(resp, limit, data)
+}
+
+// Send a request with the given method, the User-Agent and the custom headers.
+func (r *Runner) doRequest(ctx context.Context, method string, target string) (*http.Response, error) {
+	resp, _, err := r.tracedRequest(ctx, method, target, nil, nil)
+	return resp, err
+}
+
+// Details of a request collected while it is sent.
+type requestTrace struct {
+	elapsed    time.Duration // Time taken to receive the response headers.
+	remoteAddr net.Addr      // Address of the last connection used.
+}
+
+// Send a request like doRequest with the extra headers and body, nil for
+// none, and also trace it. The rate limit waits are not counted in the elapsed time.
+func (r *Runner) tracedRequest(ctx context.Context, method string, target string, extra http.Header, body []byte) (*http.Response, requestTrace, error) {
+	var trace requestTrace
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			trace.remoteAddr = info.Conn.RemoteAddr()
+		},
+	})
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bodyReader)
+	if err != nil {
+		return nil, trace, fmt.Errorf("creating request: %w", err)
+	}
+	if userAgent := r.userAgent(); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	// Setting the header disables the transparent gzip decompression.
+	if r.encodings != "" {
+		req.Header.Set("Accept-Encoding", r.encodings)
+	}
+	r.setEvasionHeaders(req)
+	setHeaders(req, r.headers)
+	setHostHeaders(req, r.hostHeaders)
+	if request := requestOf(ctx); request != nil {
+		setHeaders(req, request.Headers)
+	}
+	setHeaders(req, extra)
+
+	// Wait for the runner to be resumed, then for both the global and the
+	// per-host rate limits.
+	if err := r.pause.Wait(ctx); err != nil {
+		return nil, trace, err
+	}
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, trace, err
+	}
+	if err := r.hostLimiter.Wait(ctx, req.URL.Host); err != nil {
+		return nil, trace, err
+	}
+	if err := r.throttle.Wait(ctx, req.URL.Host); err != nil {
+		return nil, trace, err
+	}
+
+	// Each request chain gets its own cookie jar so cookies set by a
+	// response never leak to the requests of other URLs.
+	client := r.client
+	if r.cookies != nil {
+		withJar := *r.client
+		withJar.Jar = r.cookies.jar(req.URL)
+		client = &withJar
+	}
+
+	done := r.options.Metrics.request()
+	start := time.Now()
+	resp, err := client.Do(req)
+	trace.elapsed = time.Since(start)
+	r.throttle.observe(req.URL.Host, resp, err)
+	if err != nil {
+		done(0)
+	} else {
+		done(trace.elapsed)
+	}
+	return resp, trace, err
+}
+
+// Name the address family of an IP, ipv4 or ipv6.
+func ipFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
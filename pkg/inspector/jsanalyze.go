@@ -0,0 +1,85 @@
+package inspector
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Kinds of values extracted from JavaScript files.
+const (
+	ExtractedURL      = "url"
+	ExtractedEndpoint = "endpoint"
+	ExtractedSecret   = "secret"
+)
+
+var (
+	// Absolute URLs anywhere in the source.
+	jsURLRegex = regexp.MustCompile("https?://[^\\s\"'`<>\\\\()]+")
+
+	// Quoted relative paths: /path, ./path, ../path, or a path with an
+	// extension commonly used by endpoints.
+	jsEndpointRegex = regexp.MustCompile("[\"'`]((?:/|\\.\\.?/)[A-Za-z0-9_\\-./?=&%:~+]+|[A-Za-z0-9_\\-]+/[A-Za-z0-9_\\-/.]*\\.(?:php|asp|aspx|jsp|json|action|do|cgi|html|xml|txt)(?:\\?[^\"'`\\s]*)?)[\"'`]")
+)
+
+// Report whether a result is a JavaScript file, from its content type or
+// from the extension of its URL.
+func isJavaScript(result *Result) bool {
+	contentType := result.Data.ContentType
+	if strings.Contains(contentType, "javascript") || strings.Contains(contentType, "ecmascript") {
+		return true
+	}
+	parsed, err := url.Parse(result.Host)
+	if err != nil {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(parsed.Path))
+	return ext == ".js" || ext == ".mjs"
+}
+
+// Extract the URLs, endpoints and secrets of a JavaScript body as child
+// results of the parent URL. Relative endpoints are resolved against it.
+func analyzeJavaScript(parent string, body []byte, secretRules []secretRule) []*Result {
+	base, err := url.Parse(parent)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var children []*Result
+	add := func(target string, kind string) {
+		if seen[target] {
+			return
+		}
+		seen[target] = true
+		child := &Result{Host: target, Type: TypeJSExtracted}
+		child.Data.Source = parent
+		child.Data.Extracted = kind
+		children = append(children, child)
+	}
+
+	for _, match := range jsURLRegex.FindAll(body, -1) {
+		add(strings.TrimRight(string(match), ".,;"), ExtractedURL)
+	}
+	for _, match := range jsEndpointRegex.FindAllSubmatch(body, -1) {
+		endpoint := string(match[1])
+		if strings.HasPrefix(endpoint, "//") || endpoint == "/" {
+			continue // Protocol relative URLs and lone slashes are not endpoints.
+		}
+		ref, err := url.Parse(endpoint)
+		if err != nil {
+			continue
+		}
+		add(base.ResolveReference(ref).String(), ExtractedEndpoint)
+	}
+
+	for _, secret := range findSecrets(secretRules, body) {
+		child := &Result{Host: parent, Type: TypeJSExtracted}
+		child.Data.Source = parent
+		child.Data.Extracted = ExtractedSecret
+		child.Data.Secrets = []Secret{secret}
+		children = append(children, child)
+	}
+	return children
+}
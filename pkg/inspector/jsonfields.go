@@ -0,0 +1,69 @@
+package inspector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// A field to extract from the JSON responses.
+type jsonPath struct {
+	name  string // Expression as given, naming the field in the output.
+	query string // gjson path.
+}
+
+// Array indexes of a JSONPath, $.items[0].
+var jsonIndexRegex = regexp.MustCompile(`\[(\d+)\]`)
+
+// Parse the fields to extract, either simple JSONPath expressions such as
+// $.version or $.items[0].name, or gjson paths such as items.#.name.
+func parseJSONPaths(expressions []string) ([]jsonPath, error) {
+	var paths []jsonPath
+	for _, expression := range expressions {
+		expression = strings.TrimSpace(expression)
+		if expression == "" {
+			continue
+		}
+		query := strings.TrimPrefix(strings.TrimPrefix(expression, "$"), ".")
+		query = jsonIndexRegex.ReplaceAllString(query, ".$1")
+		// Recursive descent has no gjson equivalent.
+		if query == "" || strings.Contains(expression, "..") {
+			return nil, fmt.Errorf("invalid JSON path %s", expression)
+		}
+		paths = append(paths, jsonPath{name: expression, query: query})
+	}
+	return paths, nil
+}
+
+// Report whether a result is a JSON response, application/json or a
+// +json type such as application/problem+json.
+func isJSON(result *Result) bool {
+	contentType := result.Data.ContentType
+	return strings.HasSuffix(contentType, "/json") || strings.HasSuffix(contentType, "+json")
+}
+
+// Extract the fields found in a JSON body, objects and arrays being kept as
+// JSON. Values are cut to maxSnippetLength bytes.
+func extractJSONFields(paths []jsonPath, body []byte) map[string]string {
+	if len(paths) == 0 || !gjson.ValidBytes(body) {
+		return nil
+	}
+	fields := make(map[string]string)
+	for _, path := range paths {
+		value := gjson.GetBytes(body, path.query)
+		if !value.Exists() {
+			continue
+		}
+		text := value.String()
+		if len(text) > maxSnippetLength {
+			text = text[:maxSnippetLength]
+		}
+		fields[path.name] = text
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,209 @@
+package inspector
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Max number of snippets kept per regex and max length of a snippet.
+const (
+	maxRegexSnippets = 10
+	maxSnippetLength = 100
+)
+
+// Function to check if a value matches any of the specified filters
+func matches(value string, filter string) bool {
+	if filter == "" {
+		return true // No filter applied
+	}
+	filters := strings.Split(filter, ",")
+	for _, f := range filters {
+		if strings.TrimSpace(f) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to check if a value should be excluded by any of the specified filters
+func filtered(value string, filter string) bool {
+	if filter == "" {
+		return false // No filter applied
+	}
+	return matches(value, filter)
+}
+
+// Compile the body regexes, the patterns may contain commas so they are not split.
+func compileRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	var regexes []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		regexes = append(regexes, re)
+	}
+	return regexes, nil
+}
+
+// Function to check if the body matches any of the regexes
+func matchesAny(regexes []*regexp.Regexp, body []byte) bool {
+	for _, re := range regexes {
+		if re.Match(body) {
+			return true
+		}
+	}
+	return false
+}
+
+// Collect the snippets of the body matched by the regexes.
+func regexSnippets(regexes []*regexp.Regexp, body []byte) []string {
+	var snippets []string
+	for _, re := range regexes {
+		for _, match := range re.FindAll(body, maxRegexSnippets) {
+			if len(match) > maxSnippetLength {
+				match = match[:maxSnippetLength]
+			}
+			snippets = append(snippets, string(match))
+		}
+	}
+	return snippets
+}
+
+// Function to check if the headers match any of the "Name" or "Name: value"
+// conditions, a value matches when it is contained in the header value
+func matchesHeader(header http.Header, conditions []string) bool {
+	for _, condition := range conditions {
+		name, value, hasValue := strings.Cut(condition, ":")
+		values := header.Values(strings.TrimSpace(name))
+		if !hasValue && len(values) > 0 {
+			return true
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		for _, v := range values {
+			if hasValue && strings.Contains(strings.ToLower(v), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Count the words and lines of a body the way ffuf does.
+func countWordsLines(body []byte) (int64, int64) {
+	if len(body) == 0 {
+		return 0, 0
+	}
+	return int64(len(bytes.Fields(body))), int64(bytes.Count(body, []byte("\n")) + 1)
+}
+
+// Match reports whether a result passes the configured matchers and filters.
+func (r *Runner) Match(result *Result) bool {
+	options := r.options
+
+	// Results below the minimum severity, or without any, aren't actionable.
+	if options.MinSeverity != "" && severityRank(result.Data.Severity) < severityRank(options.MinSeverity) {
+		return false
+	}
+
+	// Passive results only carry a suffix.
+	if result.Type == TypeExtension {
+		return !filtered(result.Data.Suffix, options.FilterSuffix)
+	}
+
+	data := result.Data
+	ranges := r.ranges
+
+	// Generated backup variants are only reported when they exist.
+	if options.BackupGen && (data.StatusCode < 200 || data.StatusCode >= 300) {
+		return false
+	}
+
+	// Apply matchers to filter the response.
+	if !ranges.matchCode.matches(float64(data.StatusCode)) {
+		return false
+	}
+	if !ranges.matchLength.matches(float64(data.ContentLength)) {
+		return false
+	}
+	if !matches(data.ContentType, options.MatchType) {
+		return false
+	}
+	if !matches(data.Suffix, options.MatchSuffix) {
+		return false
+	}
+	if !ranges.matchWords.matches(float64(data.Words)) {
+		return false
+	}
+	if !ranges.matchLines.matches(float64(data.Lines)) {
+		return false
+	}
+	if !ranges.matchTime.matches(float64(data.ResponseTime)) {
+		return false
+	}
+	if options.MatchRangeSupport && (data.RangeSupport == nil || !*data.RangeSupport) {
+		return false
+	}
+
+	// Apply filters to exclude the response.
+	if ranges.filterCode.filtered(float64(data.StatusCode)) {
+		return false
+	}
+	if ranges.filterLength.filtered(float64(data.ContentLength)) {
+		return false
+	}
+	if filtered(data.ContentType, options.FilterType) {
+		return false
+	}
+	if filtered(data.Suffix, options.FilterSuffix) {
+		return false
+	}
+	if ranges.filterWords.filtered(float64(data.Words)) {
+		return false
+	}
+	if ranges.filterLines.filtered(float64(data.Lines)) {
+		return false
+	}
+	if ranges.filterTime.filtered(float64(data.ResponseTime)) {
+		return false
+	}
+	if options.FilterCDN && data.CDN != "" && isStaticAsset(data.ContentType) {
+		return false
+	}
+	if options.FilterSoft404 && data.Soft404 {
+		return false
+	}
+	if !withinCutoffs(data, r.newerThan, r.olderThan) {
+		return false
+	}
+
+	// Apply the header conditions.
+	if len(options.MatchHeader) > 0 && !matchesHeader(result.Header, options.MatchHeader) {
+		return false
+	}
+	if matchesHeader(result.Header, options.FilterHeader) {
+		return false
+	}
+
+	// Apply the body regexes, to the bodies downloaded.
+	if result.Data.NotModified {
+		return true
+	}
+	if len(r.matchRegex) > 0 && !matchesAny(r.matchRegex, result.Body) {
+		return false
+	}
+	if matchesAny(r.filterRegex, result.Body) {
+		return false
+	}
+	return true
+}
+
+// MatchError reports whether a failed inspection passes the error type
+// matchers and filters.
+func (r *Runner) MatchError(err error) bool {
+	errorType := ErrorType(err)
+	return matches(errorType, r.options.MatchError) && !filtered(errorType, r.options.FilterError)
+}
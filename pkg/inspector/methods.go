@@ -0,0 +1,72 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Parse a comma separated list of request methods, upper-cased and
+// deduplicated. Methods must be valid HTTP tokens.
+func parseMethods(value string) ([]string, error) {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, method := range strings.Split(value, ",") {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if method == "" || seen[method] {
+			continue
+		}
+		if strings.IndexFunc(method, func(c rune) bool { return !isTokenChar(c) }) >= 0 {
+			return nil, fmt.Errorf("invalid request method %q", method)
+		}
+		seen[method] = true
+		methods = append(methods, method)
+	}
+	return methods, nil
+}
+
+// Report whether c may be used in an HTTP token such as a method name.
+func isTokenChar(c rune) bool {
+	return c < 0x80 && (c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+		strings.ContainsRune("!#$%&'*+-.^_`|~", c))
+}
+
+// Load the body sent with the request methods, from the body file when set.
+// It returns nil when no body is set.
+func requestBody(options *Options) ([]byte, error) {
+	if options.Body != "" && options.BodyFile != "" {
+		return nil, fmt.Errorf("only one of the body and the body file can be set")
+	}
+	if options.BodyFile != "" {
+		body, err := os.ReadFile(options.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading body file %s: %w", options.BodyFile, err)
+		}
+		return body, nil
+	}
+	if options.Body != "" {
+		return []byte(options.Body), nil
+	}
+	return nil, nil
+}
+
+// Send an OPTIONS request and return the methods of its Allow header, nil
+// when the request fails or the header is missing.
+func (r *Runner) allowedMethods(ctx context.Context, target string) []string {
+	resp, err := r.doRequest(ctx, "OPTIONS", target)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+
+	var methods []string
+	for _, allow := range resp.Header.Values("Allow") {
+		for _, method := range strings.Split(allow, ",") {
+			if method = strings.ToUpper(strings.TrimSpace(method)); method != "" {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
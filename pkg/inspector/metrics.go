@@ -0,0 +1,152 @@
+package inspector
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Upper bounds in seconds of the response time histogram buckets.
+var responseTimeBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects the requests and results of the runners sharing it, so a
+// long-running scan can be observed. It is set in Options and can be shared
+// by successive runners, a nil Metrics collecting nothing.
+type Metrics struct {
+	requests atomic.Int64
+	inFlight atomic.Int64
+	queued   atomic.Int64 // URLs waiting for a thread or a host spot.
+
+	mu           sync.Mutex
+	errors       map[string]int64 // Failed inspections by error type.
+	matches      map[string]int64 // Matched results by suffix.
+	buckets      []int64          // Response times per bucket, not cumulative.
+	responseTime float64          // Sum of the response times in seconds.
+	responses    int64
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		errors:  make(map[string]int64),
+		matches: make(map[string]int64),
+		buckets: make([]int64, len(responseTimeBuckets)+1),
+	}
+}
+
+// Count a request sent, until the returned function is called with its
+// response time, zero when it failed.
+func (m *Metrics) request() func(elapsed time.Duration) {
+	if m == nil {
+		return func(time.Duration) {}
+	}
+	m.requests.Add(1)
+	m.inFlight.Add(1)
+	return func(elapsed time.Duration) {
+		m.inFlight.Add(-1)
+		if elapsed <= 0 {
+			return
+		}
+		seconds := elapsed.Seconds()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.buckets[sort.SearchFloat64s(responseTimeBuckets, seconds)]++
+		m.responseTime += seconds
+		m.responses++
+	}
+}
+
+// Count a URL waiting to be inspected, until the returned function is called.
+func (m *Metrics) enqueue() func() {
+	if m == nil {
+		return func() {}
+	}
+	m.queued.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { m.queued.Add(-1) })
+	}
+}
+
+// Record the outcome of an inspected result.
+func (m *Metrics) record(result *Result, matched bool) {
+	if m == nil || (result.Err == nil && !matched) {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if result.Err != nil {
+		m.errors[ErrorType(result.Err)]++
+	} else {
+		m.matches[result.Data.Suffix]++
+	}
+}
+
+// WritePrometheus writes the metrics in the Prometheus text format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	errors := sortedCounts(m.errors)
+	matches := sortedCounts(m.matches)
+	buckets := append([]int64(nil), m.buckets...)
+	responseTime, responses := m.responseTime, m.responses
+	m.mu.Unlock()
+
+	b := bufio.NewWriter(w)
+	writeHeader := func(name, kind, help string) {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	writeHeader("linkinspector_requests_total", "counter", "HTTP requests sent.")
+	fmt.Fprintf(b, "linkinspector_requests_total %d\n", m.requests.Load())
+	writeHeader("linkinspector_errors_total", "counter", "Failed inspections by error type.")
+	for _, count := range errors {
+		fmt.Fprintf(b, "linkinspector_errors_total{type=\"%s\"} %d\n", escapeLabel(count.key), count.value)
+	}
+	writeHeader("linkinspector_matches_total", "counter", "Matched results by suffix.")
+	for _, count := range matches {
+		fmt.Fprintf(b, "linkinspector_matches_total{suffix=\"%s\"} %d\n", escapeLabel(count.key), count.value)
+	}
+
+	writeHeader("linkinspector_response_time_seconds", "histogram", "Time taken to receive the response headers.")
+	var cumulative int64
+	for i, bound := range responseTimeBuckets {
+		cumulative += buckets[i]
+		fmt.Fprintf(b, "linkinspector_response_time_seconds_bucket{le=\"%s\"} %d\n", strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(b, "linkinspector_response_time_seconds_bucket{le=\"+Inf\"} %d\n", responses)
+	fmt.Fprintf(b, "linkinspector_response_time_seconds_sum %s\n", strconv.FormatFloat(responseTime, 'g', -1, 64))
+	fmt.Fprintf(b, "linkinspector_response_time_seconds_count %d\n", responses)
+
+	writeHeader("linkinspector_requests_in_flight", "gauge", "HTTP requests waiting for their response.")
+	fmt.Fprintf(b, "linkinspector_requests_in_flight %d\n", m.inFlight.Load())
+	writeHeader("linkinspector_queue_depth", "gauge", "URLs waiting for a thread to be inspected.")
+	fmt.Fprintf(b, "linkinspector_queue_depth %d\n", m.queued.Load())
+	return b.Flush()
+}
+
+type labelCount struct {
+	key   string
+	value int64
+}
+
+// Copy counts sorted by label, keeping the output stable across scrapes.
+func sortedCounts(counts map[string]int64) []labelCount {
+	sorted := make([]labelCount, 0, len(counts))
+	for key, value := range counts {
+		sorted = append(sorted, labelCount{key, value})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].key < sorted[j].key })
+	return sorted
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
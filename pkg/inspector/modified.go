@@ -0,0 +1,65 @@
+package inspector
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse the Last-Modified header of a response into RFC 3339, empty when
+// missing or invalid.
+func lastModified(header http.Header) string {
+	modified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return ""
+	}
+	return modified.UTC().Format(time.RFC3339)
+}
+
+// Parse the cutoff of an age filter: a date (2024-01-31), an RFC 3339 time
+// or an age before now with a unit, such as 36h, 7d, 2w or 1y.
+func parseCutoff(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	days := map[byte]int{'d': 1, 'w': 7, 'y': 365}
+	if unit, ok := days[value[len(value)-1]]; ok {
+		count, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || count < 0 {
+			return time.Time{}, fmt.Errorf("invalid age %s, use a date such as 2024-01-31 or an age such as 36h, 7d, 2w or 1y", value)
+		}
+		return now.AddDate(0, 0, -count*unit), nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return time.Time{}, fmt.Errorf("invalid age %s, use a date such as 2024-01-31 or an age such as 36h, 7d, 2w or 1y", value)
+	}
+	return now.Add(-age), nil
+}
+
+// Report whether the Last-Modified time of a result is within the newer
+// than and older than cutoffs, zero for none. Results without a
+// Last-Modified header are outside of any cutoff.
+func withinCutoffs(data Data, newerThan, olderThan time.Time) bool {
+	if newerThan.IsZero() && olderThan.IsZero() {
+		return true
+	}
+	modified, err := time.Parse(time.RFC3339, data.LastModified)
+	if err != nil {
+		return false
+	}
+	if !newerThan.IsZero() && !modified.After(newerThan) {
+		return false
+	}
+	return olderThan.IsZero() || modified.Before(olderThan)
+}
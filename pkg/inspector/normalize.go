@@ -0,0 +1,54 @@
+package inspector
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidURL is returned for input lines that can't be turned into an HTTP URL.
+var ErrInvalidURL = errors.New("invalid URL")
+
+// Characters that are not allowed unescaped anywhere in a URL.
+const unsafeChars = " \"<>\\^`{|}"
+
+// NormalizeURL turns a raw input line into a requestable URL. The default
+// scheme is added when missing, the fragment is dropped and characters that
+// are invalid in a URL are percent-encoded.
+func NormalizeURL(raw string, defaultScheme string) (string, error) {
+	raw = strings.TrimSpace(raw)
+
+	// Fragments are never sent to the server.
+	raw, _, _ = strings.Cut(raw, "#")
+	if raw == "" {
+		return "", fmt.Errorf("%w: empty URL", ErrInvalidURL)
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = defaultScheme + "://" + strings.TrimPrefix(raw, "//")
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c < 0x21 || c >= 0x7f || strings.IndexByte(unsafeChars, c) >= 0 {
+			fmt.Fprintf(&sb, "%%%02X", c)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+
+	u, err := url.Parse(sb.String())
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: unsupported scheme %q", ErrInvalidURL, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	return u.String(), nil
+}
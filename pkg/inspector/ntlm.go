@@ -0,0 +1,168 @@
+package inspector
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// NTLM negotiate flags: unicode, request target, NTLM, always sign,
+// extended session security, target info, 128 and 56 bit keys.
+const ntlmFlags = 0x00000001 | 0x00000004 | 0x00000200 | 0x00008000 | 0x00080000 | 0x00800000 | 0x20000000 | 0x80000000
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// Build the base64 negotiate message starting an NTLM handshake.
+func ntlmNegotiate() string {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmFlags)
+	return base64.StdEncoding.EncodeToString(msg)
+}
+
+// Find the base64 challenge message among the WWW-Authenticate headers.
+func ntlmChallenge(headers []string) ([]byte, bool) {
+	for _, header := range headers {
+		scheme, token, _ := strings.Cut(strings.TrimSpace(header), " ")
+		if !strings.EqualFold(scheme, "NTLM") || token == "" {
+			continue
+		}
+		challenge, err := base64.StdEncoding.DecodeString(strings.TrimSpace(token))
+		if err == nil {
+			return challenge, true
+		}
+	}
+	return nil, false
+}
+
+// Build the base64 authenticate message answering an NTLM challenge with an
+// NTLMv2 response from the 8 byte client challenge at the given time. The
+// username may be given as DOMAIN\user.
+func ntlmAuthenticate(challenge []byte, username, password string, clientChallenge []byte, now time.Time) (string, error) {
+	if len(challenge) < 48 || !bytes.Equal(challenge[:8], ntlmSignature) || binary.LittleEndian.Uint32(challenge[8:]) != 2 {
+		return "", errors.New("invalid NTLM challenge")
+	}
+	flags := binary.LittleEndian.Uint32(challenge[20:]) & ntlmFlags
+	serverChallenge := challenge[24:32]
+	infoLen := int(binary.LittleEndian.Uint16(challenge[40:]))
+	infoOffset := int(binary.LittleEndian.Uint32(challenge[44:]))
+	if infoOffset+infoLen > len(challenge) {
+		return "", errors.New("invalid NTLM challenge target info")
+	}
+	targetInfo := challenge[infoOffset : infoOffset+infoLen]
+
+	domain, user, ok := strings.Cut(username, `\`)
+	if !ok {
+		domain, user = "", username
+	}
+
+	// NTOWFv2, keyed by the MD4 hash of the password.
+	ntHash := md4Sum(utf16LE(password))
+	mac := hmac.New(md5.New, ntHash[:])
+	mac.Write(utf16LE(strings.ToUpper(user) + domain))
+	responseKey := mac.Sum(nil)
+
+	// Windows FILETIME: 100ns intervals since 1601.
+	timestamp := make([]byte, 8)
+	binary.LittleEndian.PutUint64(timestamp, uint64(now.Unix()*1e7+int64(now.Nanosecond()/100)+116444736000000000))
+
+	var blob bytes.Buffer
+	blob.Write([]byte{1, 1, 0, 0, 0, 0, 0, 0})
+	blob.Write(timestamp)
+	blob.Write(clientChallenge)
+	blob.Write([]byte{0, 0, 0, 0})
+	blob.Write(targetInfo)
+	blob.Write([]byte{0, 0, 0, 0})
+
+	mac = hmac.New(md5.New, responseKey)
+	mac.Write(serverChallenge)
+	mac.Write(blob.Bytes())
+	ntResponse := append(mac.Sum(nil), blob.Bytes()...)
+
+	mac = hmac.New(md5.New, responseKey)
+	mac.Write(serverChallenge)
+	mac.Write(clientChallenge)
+	lmResponse := append(mac.Sum(nil), clientChallenge...)
+
+	// Header of security buffers followed by their payloads.
+	payloads := [][]byte{lmResponse, ntResponse, utf16LE(domain), utf16LE(user), nil, nil}
+	msg := make([]byte, 64)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+	for i, payload := range payloads {
+		field := msg[12+8*i:]
+		binary.LittleEndian.PutUint16(field, uint16(len(payload)))
+		binary.LittleEndian.PutUint16(field[2:], uint16(len(payload)))
+		binary.LittleEndian.PutUint32(field[4:], uint32(len(msg)))
+		msg = append(msg, payload...)
+	}
+	return base64.StdEncoding.EncodeToString(msg), nil
+}
+
+// Encode a string as UTF-16 little endian.
+func utf16LE(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(encoded))
+	for i, c := range encoded {
+		binary.LittleEndian.PutUint16(b[2*i:], c)
+	}
+	return b
+}
+
+// Compute the MD4 digest (RFC 1320) used by NTLM to hash passwords.
+func md4Sum(data []byte) [16]byte {
+	length := uint64(len(data)) * 8
+	msg := append(append([]byte{}, data...), 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	msg = binary.LittleEndian.AppendUint64(msg, length)
+
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+	var x [16]uint32
+	for block := 0; block < len(msg); block += 64 {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[block+4*i:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+
+		// Round 1.
+		for _, i := range []int{0, 4, 8, 12} {
+			a = bits.RotateLeft32(a+(b&c|^b&d)+x[i], 3)
+			d = bits.RotateLeft32(d+(a&b|^a&c)+x[i+1], 7)
+			c = bits.RotateLeft32(c+(d&a|^d&b)+x[i+2], 11)
+			b = bits.RotateLeft32(b+(c&d|^c&a)+x[i+3], 19)
+		}
+		// Round 2.
+		for _, i := range []int{0, 1, 2, 3} {
+			a = bits.RotateLeft32(a+(b&c|b&d|c&d)+x[i]+0x5a827999, 3)
+			d = bits.RotateLeft32(d+(a&b|a&c|b&c)+x[i+4]+0x5a827999, 5)
+			c = bits.RotateLeft32(c+(d&a|d&b|a&b)+x[i+8]+0x5a827999, 9)
+			b = bits.RotateLeft32(b+(c&d|c&a|d&a)+x[i+12]+0x5a827999, 13)
+		}
+		// Round 3.
+		for _, i := range []int{0, 2, 1, 3} {
+			a = bits.RotateLeft32(a+(b^c^d)+x[i]+0x6ed9eba1, 3)
+			d = bits.RotateLeft32(d+(a^b^c)+x[i+8]+0x6ed9eba1, 9)
+			c = bits.RotateLeft32(c+(d^a^b)+x[i+4]+0x6ed9eba1, 11)
+			b = bits.RotateLeft32(b+(c^d^a)+x[i+12]+0x6ed9eba1, 15)
+		}
+
+		a, b, c, d = a+aa, b+bb, c+cc, d+dd
+	}
+
+	var sum [16]byte
+	for i, v := range []uint32{a, b, c, d} {
+		binary.LittleEndian.PutUint32(sum[4*i:], v)
+	}
+	return sum
+}
@@ -0,0 +1,99 @@
+package inspector
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TypeOpenRedirect marks a URL redirecting to the domain injected in one of
+// its parameters, the Host being the URL with the injected value.
+const TypeOpenRedirect = "OPEN REDIRECT"
+
+// OpenRedirectInfo holds the details of a confirmed open redirect.
+type OpenRedirectInfo struct {
+	Parameter string `json:"parameter"`
+	Location  string `json:"location"`
+}
+
+// Domain injected in the redirect parameters, reserved so the redirects
+// lead nowhere even if followed by another tool.
+const redirectCanary = "canary.example.com"
+
+// Values injected in turn, an absolute URL then a scheme relative one,
+// which slips through the checks only rejecting schemes.
+var redirectPayloads = []string{"https://" + redirectCanary + "/", "//" + redirectCanary + "/"}
+
+// Query parameters commonly holding the URL to redirect to, lower case.
+var redirectParams = map[string]bool{
+	"url": true, "uri": true, "u": true, "link": true, "next": true, "redirect": true, "redirect_url": true,
+	"redirect_uri": true, "redirecturl": true, "redirecturi": true, "redir": true, "return": true,
+	"return_to": true, "returnto": true, "return_url": true, "returnurl": true, "goto": true,
+	"dest": true, "destination": true, "continue": true, "target": true, "to": true, "out": true,
+	"forward": true, "rurl": true, "callback": true, "success_url": true, "checkout_url": true,
+}
+
+// Marks the requests whose redirects must not be followed, whatever the options.
+type noRedirectKey struct{}
+
+// Inject the canary domain in the redirect parameters of a URL, one at a
+// time, and return the open redirects found: the responses whose Location
+// leads to the canary. Parameters of a path are only checked once.
+func (r *Runner) checkOpenRedirect(ctx context.Context, target string) []*Result {
+	normalized, err := NormalizeURL(target, r.options.DefaultScheme)
+	if err != nil {
+		return nil
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return nil
+	}
+
+	query := parsed.Query()
+	var results []*Result
+	for param := range query {
+		if !redirectParams[strings.ToLower(param)] {
+			continue
+		}
+		endpoint := parsed.Scheme + "://" + parsed.Host + parsed.Path + "?" + param
+		if _, checked := r.redirects.LoadOrStore(endpoint, true); checked {
+			continue
+		}
+		for _, payload := range redirectPayloads {
+			injected := *parsed
+			values := parsed.Query()
+			values.Set(param, payload)
+			injected.RawQuery = values.Encode()
+			if result := r.probeRedirect(ctx, injected.String(), param); result != nil {
+				results = append(results, result)
+				break
+			}
+		}
+	}
+	return results
+}
+
+// Request a URL without following its redirect and return an open redirect
+// result when the Location leads to the canary domain, nil otherwise.
+func (r *Runner) probeRedirect(ctx context.Context, target string, param string) *Result {
+	resp, err := r.doRequest(context.WithValue(ctx, noRedirectKey{}, true), "GET", target)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil || !strings.EqualFold(location.Hostname(), redirectCanary) {
+		return nil
+	}
+	result := &Result{Host: target, Type: TypeOpenRedirect}
+	result.Data.StatusCode = int64(resp.StatusCode)
+	result.Data.OpenRedirect = &OpenRedirectInfo{Parameter: param, Location: resp.Header.Get("Location")}
+	return result
+}
+
+// Report whether the redirects of a request must be followed.
+func followsRedirects(req *http.Request) bool {
+	return req.Context().Value(noRedirectKey{}) == nil
+}
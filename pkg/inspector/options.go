@@ -0,0 +1,137 @@
+package inspector
+
+import "time"
+
+// Options controls how URLs are inspected and which results are kept.
+type Options struct {
+	Passive                 bool
+	BackupGen               bool
+	Expand                  bool
+	Crawl                   bool
+	CrawlDepth              int
+	CrawlBudget             int
+	ExtensionsFile          string
+	MIMEFile                string
+	Method                  string
+	RequestMethods          string
+	Body                    string
+	BodyFile                string
+	AllowedMethods          bool
+	DefaultScheme           string
+	Scope                   []string
+	OutOfScope              []string
+	ScopeFile               string
+	AllowInternal           bool
+	BlockDomains            []string
+	ProbeAllSchemes         bool
+	AllSchemes              bool
+	Ports                   string
+	PathsFile               string
+	Wordlist                string
+	Sniff                   bool
+	SniffSize               int
+	ReadBody                bool
+	MaxBodySize             int
+	Compression             []string
+	TechDetect              bool
+	JSAnalyze               bool
+	Secrets                 bool
+	ExtractJSON             []string
+	HTMLMeta                bool
+	ExtractLinks            bool
+	ZipPeek                 bool
+	RangeProbe              bool
+	TLSProbe                bool
+	SecurityHeaders         bool
+	CDNDetect               bool
+	DNSDetails              bool
+	RemoteAddr              bool
+	IPv4Only                bool
+	IPv6Only                bool
+	SourceIPs               []string
+	Interface               string
+	Soft404                 bool
+	Hash                    string
+	ScreenshotDir           string
+	ScreenshotBrowser       string
+	IncludeHeaders          bool
+	MatchCode               string
+	MatchLength             string
+	MatchType               string
+	MatchSuffix             string
+	MatchWords              string
+	MatchLines              string
+	MatchTime               string
+	MatchRangeSupport       bool
+	FilterCode              string
+	FilterLength            string
+	FilterType              string
+	FilterSuffix            string
+	FilterWords             string
+	FilterLines             string
+	FilterTime              string
+	FilterCDN               bool
+	FilterSoft404           bool
+	FilterDuplicatesPerHost bool
+	Unique                  string
+	MinSeverity             string
+	NewerThan               string
+	OlderThan               string
+	MatchRegex              []string
+	FilterRegex             []string
+	MatchHeader             []string
+	FilterHeader            []string
+	MatchError              string
+	FilterError             string
+	Threads                 int
+	PreserveOrder           bool
+	RateLimit               int
+	RateLimitPerHost        int
+	AutoThrottle            bool
+	HostConcurrency         int
+	UserAgent               string
+	UserAgentFile           string
+	RandomAgent             bool
+	RandomAcceptLanguage    bool
+	VaryHeaders             bool
+	SpoofForwardedFor       bool
+	Headers                 []string
+	HeaderFile              string
+	HostHeadersFile         string
+	HostHeader              string
+	Cookie                  string
+	CookieFile              string
+	SecretsFile             string
+	RulesFile               string
+	GitCheck                bool
+	OpenRedirect            bool
+	SeverityFile            string
+	ResolversFile           string
+	Timeout                 int
+	ConnectTimeout          time.Duration
+	TLSTimeout              time.Duration
+	ResponseHeaderTimeout   time.Duration
+	Insecure                bool
+	ClientCert              string
+	ClientKey               string
+	TLSMinVersion           string
+	TLSMaxVersion           string
+	TLSCiphers              string
+	SNI                     string
+	HTTP2                   bool
+	ForceHTTP1              bool
+	MaxIdleConnsPerHost     int
+	DisableKeepAlive        bool
+	FollowRedirects         bool
+	PersistCookies          bool
+	MaxRedirects            int
+	Proxy                   string
+	ProxyAuth               string
+	AuthBasic               string
+	AuthBearer              string
+	AuthDigest              string
+	AuthNTLM                string
+	Delay                   time.Duration
+	// Metrics collects the requests and results when set, see NewMetrics.
+	Metrics *Metrics
+}
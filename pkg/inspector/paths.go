@@ -0,0 +1,63 @@
+package inspector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Read the deduplicated entries of a wordlist, skipping blank and "#"
+// comment lines. The kind of wordlist names it in errors.
+func readWordlist(name string, kind string) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s %s: %w", kind, name, err)
+	}
+	defer file.Close()
+
+	seen := make(map[string]bool)
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || seen[line] {
+			continue
+		}
+		seen[line] = true
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s %s: %w", kind, name, err)
+	}
+	return entries, nil
+}
+
+// Load the paths of the wordlist appended to the input URLs, without their
+// leading slash. It returns nil when no wordlist is set.
+func loadPaths(options *Options) ([]string, error) {
+	if options.PathsFile == "" {
+		return nil, nil
+	}
+	lines, err := readWordlist(options.PathsFile, "paths file")
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var paths []string
+	for _, line := range lines {
+		if path := strings.TrimPrefix(line, "/"); path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// Append a path of the wordlist to a base URL, in place of its query and
+// fragment. Bare hosts keep no scheme so the scheme options still apply.
+func joinPath(base string, path string) string {
+	base, _, _ = strings.Cut(base, "#")
+	base, _, _ = strings.Cut(base, "?")
+	return strings.TrimSuffix(base, "/") + "/" + path
+}
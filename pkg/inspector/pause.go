@@ -0,0 +1,55 @@
+package inspector
+
+import (
+	"context"
+	"sync"
+)
+
+// Holds the requests back while the runner is paused.
+type pauseGate struct {
+	mu      sync.Mutex
+	resumed chan struct{} // Closed on resume, nil when not paused.
+}
+
+// Pause stops sending new requests until Resume is called, the requests in
+// flight being completed. Inspections wait meanwhile instead of failing.
+func (r *Runner) Pause() {
+	r.pause.mu.Lock()
+	defer r.pause.mu.Unlock()
+	if r.pause.resumed == nil {
+		r.pause.resumed = make(chan struct{})
+	}
+}
+
+// Resume sends requests again after Pause.
+func (r *Runner) Resume() {
+	r.pause.mu.Lock()
+	defer r.pause.mu.Unlock()
+	if r.pause.resumed != nil {
+		close(r.pause.resumed)
+		r.pause.resumed = nil
+	}
+}
+
+// Paused reports whether the runner is paused.
+func (r *Runner) Paused() bool {
+	r.pause.mu.Lock()
+	defer r.pause.mu.Unlock()
+	return r.pause.resumed != nil
+}
+
+// Block while the runner is paused, until resumed or the context is done.
+func (g *pauseGate) Wait(ctx context.Context) error {
+	g.mu.Lock()
+	resumed := g.resumed
+	g.mu.Unlock()
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
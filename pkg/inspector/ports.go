@@ -0,0 +1,88 @@
+package inspector
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// A port probed on bare hosts, with its scheme when forced by "scheme:port".
+type portProbe struct {
+	scheme string
+	port   int
+}
+
+// Schemes of the usual web ports, other ports use the default scheme.
+var portSchemes = map[int]string{80: "http", 8000: "http", 8080: "http", 443: "https", 8443: "https"}
+
+// Parse a comma separated list of ports, ranges (8000-8010) and ports with
+// a forced scheme (https:8000), deduplicated.
+func parsePorts(value string) ([]portProbe, error) {
+	seen := make(map[portProbe]bool)
+	var ports []portProbe
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		scheme, portRange, found := strings.Cut(entry, ":")
+		if !found {
+			scheme, portRange = "", entry
+		} else if scheme != "http" && scheme != "https" {
+			return nil, fmt.Errorf("invalid port %q, the scheme must be http or https", entry)
+		}
+
+		low, high, isRange := strings.Cut(portRange, "-")
+		if !isRange {
+			high = low
+		}
+		first, err1 := strconv.Atoi(low)
+		last, err2 := strconv.Atoi(high)
+		if err1 != nil || err2 != nil || first < 1 || last > 65535 || first > last {
+			return nil, fmt.Errorf("invalid port %q", entry)
+		}
+		for port := first; port <= last; port++ {
+			probe := portProbe{scheme: scheme, port: port}
+			if !seen[probe] {
+				seen[probe] = true
+				ports = append(ports, probe)
+			}
+		}
+	}
+	return ports, nil
+}
+
+// Expand a bare host (example.com, 10.0.0.1, [::1]) into one URL per port,
+// the scheme coming from the port. Ports without a known scheme are left
+// without one, so the default scheme or -probe-all-schemes applies. Other
+// inputs are returned as is.
+func portVariants(target string, ports []portProbe) []string {
+	host := strings.TrimSuffix(strings.TrimSpace(target), "/")
+	if strings.Contains(host, "://") {
+		return []string{target}
+	}
+	parsed, err := url.Parse("//" + host)
+	if err != nil || parsed.Hostname() == "" || parsed.Port() != "" || parsed.Path != "" || parsed.RawQuery != "" || parsed.User != nil {
+		return []string{target}
+	}
+
+	variants := make([]string, 0, len(ports))
+	for _, probe := range ports {
+		scheme := probe.scheme
+		if scheme == "" {
+			scheme = portSchemes[probe.port]
+		}
+		address := net.JoinHostPort(parsed.Hostname(), strconv.Itoa(probe.port))
+		switch {
+		case scheme == "":
+			variants = append(variants, address)
+		case (scheme == "http" && probe.port == 80) || (scheme == "https" && probe.port == 443):
+			variants = append(variants, scheme+"://"+parsed.Host)
+		default:
+			variants = append(variants, scheme+"://"+address)
+		}
+	}
+	return variants
+}
@@ -0,0 +1,162 @@
+package inspector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A single numeric condition: an exact value, a comparison or an inclusive range.
+type predicate struct {
+	op   string // "=", "<", "<=", ">", ">=" or "-" for a range
+	low  float64
+	high float64
+}
+
+// Report whether value satisfies the predicate.
+func (p predicate) match(value float64) bool {
+	switch p.op {
+	case "<":
+		return value < p.low
+	case "<=":
+		return value <= p.low
+	case ">":
+		return value > p.low
+	case ">=":
+		return value >= p.low
+	case "-":
+		return value >= p.low && value <= p.high
+	default:
+		return value == p.low
+	}
+}
+
+// A comma separated list of predicates, a value matches if any predicate does.
+type rangeMatcher []predicate
+
+// Function to check if a value matches any of the predicates
+func (m rangeMatcher) matches(value float64) bool {
+	if len(m) == 0 {
+		return true // No filter applied
+	}
+	for _, p := range m {
+		if p.match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Function to check if a value should be excluded by any of the predicates
+func (m rangeMatcher) filtered(value float64) bool {
+	if len(m) == 0 {
+		return false // No filter applied
+	}
+	return m.matches(value)
+}
+
+// Parse a matcher expression such as "200,301-399,>=500". Values are parsed
+// with parseValue, which allows units for time based matchers.
+func parseRangeMatcher(expr string, parseValue func(string) (float64, error)) (rangeMatcher, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	var m rangeMatcher
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p, err := parsePredicate(part, parseValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher value %q: %w", part, err)
+		}
+		m = append(m, p)
+	}
+	return m, nil
+}
+
+// Parse a single exact value, comparison or range.
+func parsePredicate(part string, parseValue func(string) (float64, error)) (predicate, error) {
+	// Exact values first, so negative numbers like -1 aren't taken for ranges
+	if value, err := parseValue(part); err == nil {
+		return predicate{op: "=", low: value}, nil
+	}
+
+	for _, op := range []string{"<=", ">=", "<", ">"} {
+		if rest, ok := strings.CutPrefix(part, op); ok {
+			value, err := parseValue(strings.TrimSpace(rest))
+			return predicate{op: op, low: value}, err
+		}
+	}
+
+	if i := strings.Index(part[1:], "-"); i >= 0 {
+		low, err := parseValue(strings.TrimSpace(part[:i+1]))
+		if err != nil {
+			return predicate{}, err
+		}
+		high, err := parseValue(strings.TrimSpace(part[i+2:]))
+		if err != nil {
+			return predicate{}, err
+		}
+		if low > high {
+			return predicate{}, fmt.Errorf("range start is greater than its end")
+		}
+		return predicate{op: "-", low: low, high: high}, nil
+	}
+	return predicate{}, fmt.Errorf("expected a number, a comparison or a range")
+}
+
+// Parse a plain number.
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// Parse a duration in milliseconds, a unit such as 2s or 500ms may be given.
+func parseMilliseconds(value string) (float64, error) {
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return float64(duration) / float64(time.Millisecond), nil
+}
+
+// Numeric matchers and filters parsed once from the options.
+type rangeMatchers struct {
+	matchCode, matchLength, matchWords, matchLines, matchTime      rangeMatcher
+	filterCode, filterLength, filterWords, filterLines, filterTime rangeMatcher
+}
+
+// Parse all the numeric matchers and filters of the options.
+func parseRangeMatchers(options *Options) (*rangeMatchers, error) {
+	m := &rangeMatchers{}
+	exprs := []struct {
+		target     *rangeMatcher
+		expr       string
+		parseValue func(string) (float64, error)
+	}{
+		{&m.matchCode, options.MatchCode, parseNumber},
+		{&m.matchLength, options.MatchLength, parseNumber},
+		{&m.matchWords, options.MatchWords, parseNumber},
+		{&m.matchLines, options.MatchLines, parseNumber},
+		{&m.matchTime, options.MatchTime, parseMilliseconds},
+		{&m.filterCode, options.FilterCode, parseNumber},
+		{&m.filterLength, options.FilterLength, parseNumber},
+		{&m.filterWords, options.FilterWords, parseNumber},
+		{&m.filterLines, options.FilterLines, parseNumber},
+		{&m.filterTime, options.FilterTime, parseMilliseconds},
+	}
+	for _, e := range exprs {
+		parsed, err := parseRangeMatcher(e.expr, e.parseValue)
+		if err != nil {
+			return nil, err
+		}
+		*e.target = parsed
+	}
+	return m, nil
+}
@@ -0,0 +1,89 @@
+package inspector
+
+import "testing"
+
+func TestParseRangeMatcher(t *testing.T) {
+	tests := []struct {
+		name       string
+		expr       string
+		parseValue func(string) (float64, error)
+		matches    []float64
+		misses     []float64
+		wantErr    bool
+	}{
+		{name: "empty", expr: "", parseValue: parseNumber, matches: []float64{0, 200, -1}},
+		{name: "exact", expr: "200", parseValue: parseNumber, matches: []float64{200}, misses: []float64{201, 0}},
+		{name: "exact list", expr: "200, 302", parseValue: parseNumber, matches: []float64{200, 302}, misses: []float64{301}},
+		{name: "negative exact", expr: "-1", parseValue: parseNumber, matches: []float64{-1}, misses: []float64{0, 1}},
+		{name: "range", expr: "300-399", parseValue: parseNumber, matches: []float64{300, 350, 399}, misses: []float64{299, 400}},
+		{name: "range from negative", expr: "-1-10", parseValue: parseNumber, matches: []float64{-1, 0, 10}, misses: []float64{-2, 11}},
+		{name: "less than", expr: "<100", parseValue: parseNumber, matches: []float64{99}, misses: []float64{100}},
+		{name: "less or equal", expr: "<=100", parseValue: parseNumber, matches: []float64{100}, misses: []float64{101}},
+		{name: "greater than", expr: ">100", parseValue: parseNumber, matches: []float64{101}, misses: []float64{100}},
+		{name: "greater or equal", expr: ">= 100", parseValue: parseNumber, matches: []float64{100}, misses: []float64{99}},
+		{name: "mixed", expr: "200,301-399,>=500", parseValue: parseNumber, matches: []float64{200, 302, 503}, misses: []float64{404}},
+		{name: "milliseconds", expr: "<500ms", parseValue: parseMilliseconds, matches: []float64{499}, misses: []float64{500}},
+		{name: "seconds", expr: ">2s", parseValue: parseMilliseconds, matches: []float64{2001}, misses: []float64{2000}},
+		{name: "plain milliseconds", expr: "100-1s", parseValue: parseMilliseconds, matches: []float64{100, 1000}, misses: []float64{1001}},
+		{name: "reversed range", expr: "399-300", parseValue: parseNumber, wantErr: true},
+		{name: "garbage", expr: "abc", parseValue: parseNumber, wantErr: true},
+		{name: "garbage comparison", expr: ">abc", parseValue: parseNumber, wantErr: true},
+		{name: "garbage range end", expr: "1-x", parseValue: parseNumber, wantErr: true},
+		{name: "unit without time parser", expr: "2s", parseValue: parseNumber, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matcher, err := parseRangeMatcher(test.expr, test.parseValue)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("parseRangeMatcher(%q) = %v, want an error", test.expr, matcher)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRangeMatcher(%q): %v", test.expr, err)
+			}
+			for _, value := range test.matches {
+				if !matcher.matches(value) {
+					t.Errorf("%q doesn't match %v", test.expr, value)
+				}
+			}
+			for _, value := range test.misses {
+				if matcher.matches(value) {
+					t.Errorf("%q matches %v", test.expr, value)
+				}
+			}
+		})
+	}
+}
+
+func TestRangeMatcherFiltered(t *testing.T) {
+	var empty rangeMatcher
+	if empty.filtered(404) {
+		t.Error("an empty filter excludes 404")
+	}
+	filter, err := parseRangeMatcher("400-499", parseNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filter.filtered(404) || filter.filtered(200) {
+		t.Error("400-499 doesn't exclude only 404")
+	}
+}
+
+func TestParseRangeMatchers(t *testing.T) {
+	matchers, err := parseRangeMatchers(&Options{MatchCode: "200-299", FilterTime: ">2s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !matchers.matchCode.matches(204) || matchers.matchCode.matches(404) {
+		t.Error("match code 200-299 not applied")
+	}
+	if !matchers.filterTime.filtered(2500) || matchers.filterTime.filtered(1500) {
+		t.Error("filter time >2s not applied")
+	}
+
+	if _, err := parseRangeMatchers(&Options{FilterLength: "10-1"}); err == nil {
+		t.Error("reversed filter length range accepted")
+	}
+}
@@ -0,0 +1,82 @@
+package inspector
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A token bucket with a burst of one, handing out one token per interval.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// Create a limiter allowing rate requests per second, nil when unlimited.
+func newLimiter(rate int) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &limiter{interval: time.Second / time.Duration(rate)}
+}
+
+// Block until a token is available or the context is done.
+func (l *limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Limiters created lazily for each host.
+type hostLimiter struct {
+	mu       sync.Mutex
+	rate     int
+	limiters map[string]*limiter
+}
+
+// Create a per-host limiter allowing rate requests per second, nil when unlimited.
+func newHostLimiter(rate int) *hostLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &hostLimiter{rate: rate, limiters: make(map[string]*limiter)}
+}
+
+// Block until a token is available for host or the context is done.
+func (h *hostLimiter) Wait(ctx context.Context, host string) error {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	l, ok := h.limiters[host]
+	if !ok {
+		l = newLimiter(h.rate)
+		h.limiters[host] = l
+	}
+	h.mu.Unlock()
+
+	return l.Wait(ctx)
+}
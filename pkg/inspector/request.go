@@ -0,0 +1,42 @@
+package inspector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Request is an input URL inspected with its own method, headers and body,
+// as sent to RunRequests. The URLs derived from it, such as the crawled
+// links or the paths of the wordlist, are inspected as plain URLs.
+type Request struct {
+	URL     string
+	Method  string      // Replaces the request methods, POST when empty with a body.
+	Headers http.Header // Sent on top of the custom headers, with every request for the URL.
+	Body    []byte
+}
+
+// Carries the Request of an inspection to its requests.
+type requestKey struct{}
+
+// Attach a custom request to the context of an inspection, unless nil.
+func withRequest(ctx context.Context, request *Request) context.Context {
+	if request == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// Return the custom request of an inspection, nil for a plain URL.
+func requestOf(ctx context.Context) *Request {
+	request, _ := ctx.Value(requestKey{}).(*Request)
+	return request
+}
+
+// Return the method of a custom request, empty when it uses the configured ones.
+func (request *Request) method() string {
+	if request.Method == "" && request.Body != nil {
+		return "POST"
+	}
+	return strings.ToUpper(request.Method)
+}
@@ -0,0 +1,96 @@
+package inspector
+
+import "net/http"
+
+const (
+	// TypeRequest marks results built from an HTTP response.
+	TypeRequest = "REQUEST BASED"
+	// TypeExtension marks results built from the URL extension in passive mode.
+	TypeExtension = "EXTENSION BASED"
+	// TypeJSExtracted marks the URLs, endpoints and secrets extracted from a
+	// JavaScript file, the file URL being the Source.
+	TypeJSExtracted = "JS EXTRACTED"
+)
+
+// Result holds the information collected for a single URL.
+type Result struct {
+	Host string `json:"host"`
+	Type string `json:"type"`
+	Data Data   `json:"data"`
+
+	// Header holds the response headers.
+	Header http.Header `json:"-"`
+
+	// Body holds the downloaded response body when reading it was needed.
+	Body []byte `json:"-"`
+
+	// Err is set when the URL could not be inspected.
+	Err error `json:"-"`
+
+	// Children holds the results extracted from the response, sent after it by Run.
+	Children []*Result `json:"-"`
+
+	// Links of an HTML page to follow when crawling.
+	links []string
+}
+
+// Data holds the response details of a Result.
+type Data struct {
+	StatusCode       int64             `json:"status_code,omitempty"`
+	Method           string            `json:"method,omitempty"`
+	ContentLength    int64             `json:"content_length,omitempty"`
+	BodySize         int64             `json:"body_size,omitempty"`
+	Words            int64             `json:"words,omitempty"`
+	Lines            int64             `json:"lines,omitempty"`
+	ContentType      string            `json:"content_type,omitempty"`
+	Charset          string            `json:"charset,omitempty"`
+	ContentEncoding  string            `json:"content_encoding,omitempty"`
+	TransferSize     int64             `json:"transfer_size,omitempty"`
+	DecompressedSize int64             `json:"decompressed_size,omitempty"`
+	LastModified     string            `json:"last_modified,omitempty"`
+	ETag             string            `json:"etag,omitempty"`
+	NotModified      bool              `json:"not_modified,omitempty"`
+	AllowedMethods   []string          `json:"allowed_methods,omitempty"`
+	RangeSupport     *bool             `json:"range_support,omitempty"`
+	ResponseTime     int64             `json:"response_time_ms"`
+	Proto            string            `json:"proto,omitempty"`
+	IP               string            `json:"ip,omitempty"`
+	Port             int               `json:"port,omitempty"`
+	IPFamily         string            `json:"ip_family,omitempty"`
+	Suffix           string            `json:"suffix,omitempty"`
+	Severity         string            `json:"severity,omitempty"`
+	Rules            []string          `json:"rules,omitempty"`
+	Source           string            `json:"source,omitempty"`
+	Extracted        string            `json:"extracted,omitempty"`
+	DetectedType     string            `json:"detected_type,omitempty"`
+	TypeMismatch     bool              `json:"type_mismatch,omitempty"`
+	Soft404          bool              `json:"soft_404,omitempty"`
+	Duplicates       int               `json:"duplicates,omitempty"`
+	RegexMatches     []string          `json:"regex_matches,omitempty"`
+	Secrets          []Secret          `json:"secrets,omitempty"`
+	JSONFields       map[string]string `json:"json_fields,omitempty"`
+	Meta             *HTMLMeta         `json:"meta,omitempty"`
+	Links            []string          `json:"links,omitempty"`
+	ZipEntries       []string          `json:"zip_entries,omitempty"`
+	ZipEntryCount    int               `json:"zip_entry_count,omitempty"`
+	Technologies     []string          `json:"technologies,omitempty"`
+	CDN              string            `json:"cdn,omitempty"`
+	RedirectChain    []Redirect        `json:"redirect_chain,omitempty"`
+	FinalURL         string            `json:"final_url,omitempty"`
+	Screenshot       string            `json:"screenshot,omitempty"`
+	DNS              *DNSInfo          `json:"dns,omitempty"`
+	TLS              *TLSInfo          `json:"tls,omitempty"`
+	SecurityHeaders  *SecurityHeaders  `json:"security_headers,omitempty"`
+	Git              *GitInfo          `json:"git,omitempty"`
+	OpenRedirect     *OpenRedirectInfo `json:"open_redirect,omitempty"`
+	Hashes           map[string]string `json:"hash,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	Change           string            `json:"change,omitempty"`
+	Changes          []string          `json:"changes,omitempty"`
+}
+
+// Redirect is a single hop of a followed redirect chain.
+type Redirect struct {
+	URL        string `json:"url"`
+	StatusCode int64  `json:"status_code"`
+}
@@ -0,0 +1,97 @@
+package inspector
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A named regex matching the path of interesting files, such as exposed
+// repositories, backups and configuration files.
+type pathRule struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// Built-in path rules, matched case insensitively against the URL path.
+var builtinPathRules = []pathRule{
+	{"git-config", regexp.MustCompile(`(?i)/\.git/(config|HEAD|index)$`)},
+	{"svn-metadata", regexp.MustCompile(`(?i)/\.svn/(entries|wc\.db)$`)},
+	{"hg-metadata", regexp.MustCompile(`(?i)/\.hg/(hgrc|store)`)},
+	{"env-file", regexp.MustCompile(`(?i)/\.env(\.[\w-]+)?$`)},
+	{"backup-dir", regexp.MustCompile(`(?i)/(backups?|bak|old)/`)},
+	{"phpinfo", regexp.MustCompile(`(?i)/(phpinfo|info|php_info|test)\.php$`)},
+	{"wp-config-backup", regexp.MustCompile(`(?i)/wp-config\.php([.~_-]\w*|~)$`)},
+	{"config-backup", regexp.MustCompile(`(?i)/(config|configuration|settings|database|db)\.(php|inc|ya?ml|json|xml|ini)[.~_-]\w*$`)},
+	{"htpasswd", regexp.MustCompile(`(?i)/\.ht(passwd|access)$`)},
+	{"ds-store", regexp.MustCompile(`(?i)/\.DS_Store$`)},
+	{"aws-credentials", regexp.MustCompile(`(?i)/\.aws/(credentials|config)$`)},
+	{"ssh-key", regexp.MustCompile(`(?i)/(id_(rsa|dsa|ecdsa|ed25519)|authorized_keys)$`)},
+	{"package-credentials", regexp.MustCompile(`(?i)/(\.npmrc|\.pypirc|\.netrc|\.git-credentials|\.dockercfg|\.docker/config\.json)$`)},
+	{"sftp-config", regexp.MustCompile(`(?i)/(sftp-config\.json|\.ftpconfig|\.vscode/sftp\.json|filezilla\.xml)$`)},
+	{"server-status", regexp.MustCompile(`(?i)/server-(status|info)$`)},
+	{"spring-actuator", regexp.MustCompile(`(?i)/actuator/(env|heapdump|configprops|mappings)$`)},
+	{"web-config", regexp.MustCompile(`(?i)/(web\.config|appsettings(\.\w+)?\.json)$`)},
+	{"docker-compose", regexp.MustCompile(`(?i)/(docker-compose(\.[\w-]+)?\.ya?ml|Dockerfile)$`)},
+	{"debug-log", regexp.MustCompile(`(?i)/(debug|error|access|laravel)\.log$`)},
+}
+
+// Load the built-in path rules and the "name: regex" rules of the rules file,
+// a file rule replacing the built-in rule of the same name.
+func loadPathRules(options *Options) ([]pathRule, error) {
+	if options.RulesFile == "" {
+		return builtinPathRules, nil
+	}
+
+	data, err := os.ReadFile(options.RulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading rules file %s: %w", options.RulesFile, err)
+	}
+	patterns := make(map[string]string)
+	if err := yaml.Unmarshal(data, &patterns); err != nil {
+		return nil, fmt.Errorf("parsing rules file %s: %w", options.RulesFile, err)
+	}
+
+	var rules []pathRule
+	for _, rule := range builtinPathRules {
+		if _, ok := patterns[rule.name]; !ok {
+			rules = append(rules, rule)
+		}
+	}
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// An empty pattern disables a built-in rule.
+		if patterns[name] == "" {
+			continue
+		}
+		re, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex of path rule %s: %w", name, err)
+		}
+		rules = append(rules, pathRule{name: name, regex: re})
+	}
+	return rules, nil
+}
+
+// Return the names of the path rules matching the path of a URL.
+func matchPathRules(rules []pathRule, target string) []string {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, rule := range rules {
+		if rule.regex.MatchString(parsed.Path) {
+			names = append(names, rule.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,119 @@
+package inspector
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// ErrBlockedAddress is returned when a host resolves to an internal address
+// while internal addresses are not allowed.
+var ErrBlockedAddress = errors.New("blocked internal address")
+
+// Keeps scans of third-party link dumps away from internal networks and from
+// the blocked domains.
+type safetyFilter struct {
+	allowInternal bool
+	domains       []string        // Blocked domains, their subdomains included.
+	trusted       map[string]bool // Proxy addresses dialed without checks.
+}
+
+// Build the safety filter of the options, nil when internal addresses are
+// allowed and no domain is blocked.
+func newSafetyFilter(options *Options) (*safetyFilter, error) {
+	if options.AllowInternal && len(options.BlockDomains) == 0 {
+		return nil, nil
+	}
+
+	f := &safetyFilter{allowInternal: options.AllowInternal, trusted: make(map[string]bool)}
+	for _, domain := range options.BlockDomains {
+		domain = strings.Trim(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*."), ".")
+		if domain != "" {
+			f.domains = append(f.domains, domain)
+		}
+	}
+
+	// Local proxies such as Burp listen on loopback and must stay reachable.
+	proxies := []string{options.Proxy}
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY", "http_proxy", "https_proxy", "all_proxy"} {
+		proxies = append(proxies, os.Getenv(name))
+	}
+	for _, proxy := range proxies {
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "://") {
+			proxy = "http://" + proxy
+		}
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %s: %w", proxy, err)
+		}
+		port := proxyURL.Port()
+		if port == "" {
+			port = map[string]string{"https": "443", "socks5": "1080", "socks5h": "1080"}[proxyURL.Scheme]
+		}
+		if port == "" {
+			port = "80"
+		}
+		f.trusted[net.JoinHostPort(proxyURL.Hostname(), port)] = true
+	}
+	return f, nil
+}
+
+// Report whether the host of a URL may be requested, before resolving it.
+// URLs that can't be parsed are allowed so they are reported as invalid.
+func (f *safetyFilter) allowsHost(target string, defaultScheme string) bool {
+	normalized, err := NormalizeURL(target, defaultScheme)
+	if err != nil {
+		return true
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return true
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+
+	for _, domain := range f.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+	}
+	if f.allowInternal {
+		return true
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return !isInternalIP(ip)
+	}
+	return true
+}
+
+// Dialer control rejecting connections to internal addresses, catching the
+// hosts resolving to them and the redirects to them.
+func (f *safetyFilter) control(network, address string, _ syscall.RawConn) error {
+	if f.allowInternal {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip != nil && isInternalIP(ip) {
+		return fmt.Errorf("%w %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// Report whether an IP is loopback, private (RFC 1918 and unique local),
+// link-local or unspecified.
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
@@ -0,0 +1,126 @@
+package inspector
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Scope decides which input URLs are requested. A URL is in scope when it
+// matches an include rule, or when there are none, and no exclude rule.
+type scope struct {
+	include []scopeRule
+	exclude []scopeRule
+}
+
+// A scope pattern compiled to a regex, matched against the whole URL, the
+// host and path, or the hostname only.
+type scopeRule struct {
+	regex *regexp.Regexp
+	part  string // url, hostpath or host.
+}
+
+// Build the scope of the options and the scope file, nil when there are no
+// rules. File lines starting with "!" are exclusions.
+func newScope(options *Options) (*scope, error) {
+	includes := append([]string{}, options.Scope...)
+	excludes := append([]string{}, options.OutOfScope...)
+
+	if options.ScopeFile != "" {
+		file, err := os.Open(options.ScopeFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening scope file %s: %w", options.ScopeFile, err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			switch {
+			case line == "" || strings.HasPrefix(line, "#"):
+			case strings.HasPrefix(line, "!"):
+				excludes = append(excludes, strings.TrimSpace(line[1:]))
+			default:
+				includes = append(includes, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading scope file %s: %w", options.ScopeFile, err)
+		}
+	}
+
+	if len(includes) == 0 && len(excludes) == 0 {
+		return nil, nil
+	}
+	s := &scope{}
+	for _, pattern := range includes {
+		rule, err := compileScopeRule(pattern)
+		if err != nil {
+			return nil, err
+		}
+		s.include = append(s.include, rule)
+	}
+	for _, pattern := range excludes {
+		rule, err := compileScopeRule(pattern)
+		if err != nil {
+			return nil, err
+		}
+		s.exclude = append(s.exclude, rule)
+	}
+	return s, nil
+}
+
+// Compile a scope pattern. Patterns prefixed with "re:" are regexes matched
+// against the whole URL. Others are case-insensitive wildcards where * matches
+// anything: hostnames (*.example.com), or host and path (example.com/api/*)
+// when they contain a slash.
+func compileScopeRule(pattern string) (scopeRule, error) {
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return scopeRule{}, fmt.Errorf("invalid scope regex %q: %w", expr, err)
+		}
+		return scopeRule{regex: regex, part: "url"}, nil
+	}
+
+	part := "host"
+	if strings.Contains(pattern, "/") {
+		part = "hostpath"
+	}
+	expr := strings.ReplaceAll(regexp.QuoteMeta(strings.ToLower(pattern)), `\*`, ".*")
+	return scopeRule{regex: regexp.MustCompile("^" + expr + "$"), part: part}, nil
+}
+
+// Report whether a URL is in scope. URLs that can't be parsed are left in
+// scope so they are reported as invalid.
+func (s *scope) allows(target string, defaultScheme string) bool {
+	normalized, err := NormalizeURL(target, defaultScheme)
+	if err != nil {
+		return true
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return true
+	}
+
+	parts := map[string]string{
+		"url":      normalized,
+		"host":     strings.ToLower(parsed.Hostname()),
+		"hostpath": strings.ToLower(parsed.Hostname() + parsed.EscapedPath()),
+	}
+	matches := func(rules []scopeRule) bool {
+		for _, rule := range rules {
+			if rule.regex.MatchString(parts[rule.part]) {
+				return true
+			}
+		}
+		return false
+	}
+	if matches(s.exclude) {
+		return false
+	}
+	return len(s.include) == 0 || matches(s.include)
+}
@@ -0,0 +1,99 @@
+package inspector
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Chrome and Chromium binaries looked up in PATH for screenshots.
+var browserNames = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome"}
+
+// Characters replaced in the file names of screenshots.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Size of the browser window of screenshots.
+const screenshotWindowSize = "1366,768"
+
+// Find the browser taking screenshots and create the screenshots directory.
+func setupScreenshots(options *Options) (string, error) {
+	if err := os.MkdirAll(options.ScreenshotDir, 0755); err != nil {
+		return "", fmt.Errorf("creating screenshot directory %s: %w", options.ScreenshotDir, err)
+	}
+	if options.ScreenshotBrowser != "" {
+		browser, err := exec.LookPath(options.ScreenshotBrowser)
+		if err != nil {
+			return "", fmt.Errorf("screenshot browser %s: %w", options.ScreenshotBrowser, err)
+		}
+		return browser, nil
+	}
+	for _, name := range browserNames {
+		if browser, err := exec.LookPath(name); err == nil {
+			return browser, nil
+		}
+	}
+	return "", fmt.Errorf("no Chrome or Chromium browser found for screenshots, set one with -screenshot-browser")
+}
+
+// Save a PNG screenshot of a page with headless Chrome and return its path,
+// empty when the browser fails. Each capture uses its own browser profile so
+// captures can run concurrently.
+func (r *Runner) screenshot(ctx context.Context, target string) string {
+	profile, err := os.MkdirTemp("", "linkinspector-chrome-")
+	if err != nil {
+		return ""
+	}
+	defer os.RemoveAll(profile)
+
+	// Readable file names, the hash of the URL keeping them unique.
+	_, name, found := strings.Cut(target, "://")
+	if !found {
+		name = target
+	}
+	name = strings.Trim(unsafeFileChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	sum := sha1.Sum([]byte(target))
+	file := filepath.Join(r.options.ScreenshotDir, name+"-"+hex.EncodeToString(sum[:4])+".png")
+	os.Remove(file) // A screenshot left by a previous scan is not this one.
+
+	args := []string{
+		"--headless", "--disable-gpu", "--hide-scrollbars", "--mute-audio", "--no-first-run",
+		"--user-data-dir=" + profile,
+		"--window-size=" + screenshotWindowSize,
+		"--screenshot=" + file,
+	}
+	// The Chrome sandbox refuses to run as root.
+	if os.Geteuid() == 0 {
+		args = append(args, "--no-sandbox")
+	}
+	if r.options.Insecure {
+		args = append(args, "--ignore-certificate-errors")
+	}
+	if r.options.Proxy != "" {
+		args = append(args, "--proxy-server="+r.options.Proxy)
+	}
+	if userAgent := r.userAgent(); userAgent != "" {
+		args = append(args, "--user-agent="+userAgent)
+	}
+	args = append(args, target)
+
+	// Leave the browser time to start on top of the page load.
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(r.options.Timeout)*time.Second+10*time.Second)
+	defer cancel()
+	if err := exec.CommandContext(ctx, r.browser, args...).Run(); err != nil {
+		return ""
+	}
+	if info, err := os.Stat(file); err != nil || info.Size() == 0 {
+		return ""
+	}
+	return file
+}
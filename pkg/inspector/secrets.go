@@ -0,0 +1,98 @@
+package inspector
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Secret is a potential secret found in a response body, the match is redacted.
+type Secret struct {
+	Rule  string `json:"rule"`
+	Match string `json:"match"`
+}
+
+// A named regex matching a kind of secret.
+type secretRule struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// Built-in secret rules.
+var builtinSecretRules = []secretRule{
+	{"aws-access-key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z\-]{10,}\b`)},
+	{"stripe-secret-key", regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{24,}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`)},
+	{"private-key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{"generic-api-key", regexp.MustCompile(`(?i)\b(?:api[_\-]?key|api[_\-]?secret|access[_\-]?token|client[_\-]?secret)["']?\s*[:=]\s*["']([A-Za-z0-9_\-]{16,})["']`)},
+}
+
+// Load the built-in secret rules and the "name: regex" rules of the secrets
+// file, a file rule replacing the built-in rule of the same name.
+func loadSecretRules(options *Options) ([]secretRule, error) {
+	if options.SecretsFile == "" {
+		return builtinSecretRules, nil
+	}
+
+	data, err := os.ReadFile(options.SecretsFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets file %s: %w", options.SecretsFile, err)
+	}
+	patterns := make(map[string]string)
+	if err := yaml.Unmarshal(data, &patterns); err != nil {
+		return nil, fmt.Errorf("parsing secrets file %s: %w", options.SecretsFile, err)
+	}
+
+	var rules []secretRule
+	for _, rule := range builtinSecretRules {
+		if _, ok := patterns[rule.name]; !ok {
+			rules = append(rules, rule)
+		}
+	}
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex of secret rule %s: %w", name, err)
+		}
+		rules = append(rules, secretRule{name: name, regex: re})
+	}
+	return rules, nil
+}
+
+// Find the secrets of a body, each distinct match being reported once. The
+// first group of a rule, when it has one, captures the secret itself.
+func findSecrets(rules []secretRule, body []byte) []Secret {
+	seen := make(map[string]bool)
+	var secrets []Secret
+	for _, rule := range rules {
+		for _, submatches := range rule.regex.FindAllSubmatch(body, -1) {
+			match := string(submatches[min(1, len(submatches)-1)])
+			if seen[rule.name+match] {
+				continue
+			}
+			seen[rule.name+match] = true
+			secrets = append(secrets, Secret{Rule: rule.name, Match: redact(match)})
+		}
+	}
+	return secrets
+}
+
+// Hide the middle of a secret, keeping enough of it to identify it.
+func redact(secret string) string {
+	if len(secret) <= 12 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", len(secret)-8) + secret[len(secret)-4:]
+}
@@ -0,0 +1,57 @@
+package inspector
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SecurityHeaders holds the audit of the security headers of a response.
+type SecurityHeaders struct {
+	Grade   string   `json:"grade"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Grades by number of missing headers, the last one for any more.
+var securityGrades = []string{"A", "B", "C", "D", "F"}
+
+var hstsMaxAgeRegex = regexp.MustCompile(`(?i)max-age\s*=\s*"?(\d+)`)
+
+// Audit the security headers of a response, listing the missing ones by
+// their short names: hsts, csp, xcto, xfo and referrer. A header set to an
+// ineffective value, such as HSTS with max-age=0, counts as missing. HSTS
+// is ignored by browsers over plain HTTP, so it is always missing there.
+func auditSecurityHeaders(header http.Header, https bool) *SecurityHeaders {
+	csp := header.Get("Content-Security-Policy")
+	checks := []struct {
+		name string
+		ok   bool
+	}{
+		{"hsts", https && hstsEnabled(header.Get("Strict-Transport-Security"))},
+		{"csp", csp != ""},
+		{"xcto", strings.EqualFold(strings.TrimSpace(header.Get("X-Content-Type-Options")), "nosniff")},
+		// The frame-ancestors directive replaces X-Frame-Options.
+		{"xfo", header.Get("X-Frame-Options") != "" || strings.Contains(strings.ToLower(csp), "frame-ancestors")},
+		{"referrer", header.Get("Referrer-Policy") != ""},
+	}
+
+	audit := &SecurityHeaders{}
+	for _, check := range checks {
+		if !check.ok {
+			audit.Missing = append(audit.Missing, check.name)
+		}
+	}
+	audit.Grade = securityGrades[min(len(audit.Missing), len(securityGrades)-1)]
+	return audit
+}
+
+// Report whether a Strict-Transport-Security header enables HSTS.
+func hstsEnabled(value string) bool {
+	match := hstsMaxAgeRegex.FindStringSubmatch(value)
+	if match == nil {
+		return false
+	}
+	maxAge, err := strconv.ParseInt(match[1], 10, 64)
+	return err == nil && maxAge > 0
+}
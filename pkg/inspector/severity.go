@@ -0,0 +1,94 @@
+package inspector
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Default severities of the suffix labels, a user file in the same format is
+// merged over them.
+//
+//go:embed mappings/severity.yaml
+var builtinSeverities []byte
+
+// Severity levels, from the least to the most severe.
+var severityLevels = []string{"info", "low", "medium", "high", "critical"}
+
+// Rank of a severity level, 0 for no severity.
+func severityRank(severity string) int {
+	return slices.Index(severityLevels, severity) + 1
+}
+
+// BuiltinSeverities returns the embedded default severities of the suffix
+// labels, in the YAML format expected by the SeverityFile option.
+func BuiltinSeverities() []byte {
+	return builtinSeverities
+}
+
+// ValidateSeverityFile parses a severity file and returns its number of
+// entries along with its invalid severities.
+func ValidateSeverityFile(file string) (int, []string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, nil, fmt.Errorf("reading severity file %s: %w", file, err)
+	}
+	entries, err := parseMapping(data, file)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var problems []string
+	for label, severity := range entries {
+		if severity != "" && severityRank(strings.ToLower(severity)) == 0 {
+			problems = append(problems, fmt.Sprintf("severity %q of %q isn't one of %s", severity, label, strings.Join(severityLevels, ", ")))
+		}
+	}
+	sort.Strings(problems)
+	return len(entries), problems, nil
+}
+
+// Load the default severities and merge the severity file over them, keyed
+// by lower case suffix labels. An empty severity removes a label.
+func loadSeverities(options *Options) (map[string]string, error) {
+	builtin, err := parseMapping(builtinSeverities, "built-in severities")
+	if err != nil {
+		return nil, err
+	}
+	layers := []map[string]string{builtin}
+	if options.SeverityFile != "" {
+		data, err := os.ReadFile(options.SeverityFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading severity file %s: %w", options.SeverityFile, err)
+		}
+		entries, err := parseMapping(data, options.SeverityFile)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, entries)
+	}
+
+	severities := make(map[string]string)
+	for _, layer := range layers {
+		for label, severity := range layer {
+			label, severity = strings.ToLower(label), strings.ToLower(severity)
+			if severity == "" {
+				delete(severities, label)
+				continue
+			}
+			if severityRank(severity) == 0 {
+				return nil, fmt.Errorf("invalid severity %s of suffix %s, use %s", severity, label, strings.Join(severityLevels, ", "))
+			}
+			severities[label] = severity
+		}
+	}
+	return severities, nil
+}
+
+// Return the severity of a suffix label, empty when it has none.
+func (r *Runner) severityOf(suffix string) string {
+	return r.severities[strings.ToLower(suffix)]
+}
@@ -0,0 +1,167 @@
+package inspector
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// A magic number signature found at a fixed offset of a file.
+type signature struct {
+	offset      int
+	magic       []byte
+	contentType string
+}
+
+// Signatures checked in order before falling back to http.DetectContentType.
+var signatures = []signature{
+	// Archive
+	{0, []byte("PK\x03\x04"), "application/zip"},
+	{0, []byte("PK\x05\x06"), "application/zip"},
+	{0, []byte("Rar!\x1a\x07"), "application/vnd.rar"},
+	{0, []byte("7z\xbc\xaf\x27\x1c"), "application/x-7z-compressed"},
+	{0, []byte("\x1f\x8b\x08"), "application/gzip"},
+	{0, []byte("BZh"), "application/x-bzip2"},
+	{0, []byte("\xfd7zXZ\x00"), "application/x-xz"},
+	{0, []byte("\x28\xb5\x2f\xfd"), "application/zstd"},
+	{257, []byte("ustar"), "application/x-tar"},
+	{0, []byte("%PDF-"), "application/pdf"},
+	{0, []byte("MZ"), "application/vnd.microsoft.portable-executable"},
+	{0, []byte("\x7fELF"), "application/x-executable"},
+	{0, []byte("SQLite format 3\x00"), "application/vnd.sqlite3"},
+	{0, []byte("MSCF"), "application/vnd.ms-cab-compressed"},
+	{0, []byte("!<arch>\ndebian"), "application/vnd.debian.binary-package"},
+	{0, []byte("!<arch>"), "application/x-unix-archive"},
+	{0, []byte("\xed\xab\xee\xdb"), "application/x-rpm"},
+	{0, []byte("Cr24"), "application/x-google-chrome-extension"},
+	{0, []byte("{\\rtf"), "application/rtf"},
+	{0, []byte("%!"), "application/postscript"},
+	{0, []byte("\x00asm"), "application/wasm"},
+	{0, []byte("dex\n"), "application/vnd.android.dex"},
+	{0, []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"), "application/msword"},
+
+	// Image
+	{0, []byte("\xff\xd8\xff"), "image/jpeg"},
+	{0, []byte("\x89PNG\r\n\x1a\n"), "image/png"},
+	{0, []byte("GIF87a"), "image/gif"},
+	{0, []byte("GIF89a"), "image/gif"},
+	{0, []byte("8BPS"), "image/vnd.adobe.photoshop"},
+	{0, []byte("\x00\x00\x01\x00"), "image/vnd.microsoft.icon"},
+
+	// Audio and video
+	{0, []byte("ID3"), "audio/mpeg"},
+	{0, []byte("fLaC"), "audio/x-flac"},
+	{0, []byte("OggS"), "audio/ogg"},
+	{0, []byte("\x1a\x45\xdf\xa3"), "video/x-matroska"},
+	{0, []byte("FLV\x01"), "video/x-flv"},
+
+	// Font
+	{0, []byte("wOFF"), "application/font-woff"},
+	{0, []byte("\x00\x01\x00\x00\x00"), "application/font-sfnt"},
+}
+
+// Detect the real content type of a body prefix, magic reporting whether it
+// came from a magic number rather than the standard library sniffer.
+func sniffContentType(body []byte) (detected string, magic bool) {
+	if len(body) == 0 {
+		return "", false
+	}
+	for _, sig := range signatures {
+		end := sig.offset + len(sig.magic)
+		if len(body) >= end && bytes.Equal(body[sig.offset:end], sig.magic) {
+			return sig.contentType, true
+		}
+	}
+
+	// Fall back to the standard library sniffer for text and media types.
+	detected = strings.TrimSpace(strings.Split(http.DetectContentType(body), ";")[0])
+	if detected == "application/octet-stream" {
+		return "", false
+	}
+	return detected, false
+}
+
+// Declared types sharing the magic number of a type of the signatures, such
+// as the zip based office documents, Java and Android packages.
+var signatureAliases = map[string]string{
+	"application/x-zip-compressed":            "application/zip",
+	"application/x-zip":                       "application/zip",
+	"application/java-archive":                "application/zip",
+	"application/x-java-archive":              "application/zip",
+	"application/vnd.android.package-archive": "application/zip",
+	"application/epub+zip":                    "application/zip",
+	"application/x-xpinstall":                 "application/zip",
+	"application/vnd.ms-excel":                "application/msword",
+	"application/vnd.ms-powerpoint":           "application/msword",
+	"application/vnd.ms-outlook":              "application/msword",
+	"application/x-msi":                       "application/msword",
+	"application/x-gzip":                      "application/gzip",
+	"application/x-compressed-tar":            "application/gzip",
+	"application/x-rar-compressed":            "application/vnd.rar",
+	"application/x-bzip":                      "application/x-bzip2",
+	"application/x-zstd":                      "application/zstd",
+	"application/x-tar-compressed":            "application/x-tar",
+	"application/x-pdf":                       "application/pdf",
+	"application/x-msdownload":                "application/vnd.microsoft.portable-executable",
+	"application/x-dosexec":                   "application/vnd.microsoft.portable-executable",
+	"application/x-elf":                       "application/x-executable",
+	"application/x-sharedlib":                 "application/x-executable",
+	"application/x-sqlite3":                   "application/vnd.sqlite3",
+	"application/x-debian-package":            "application/vnd.debian.binary-package",
+	"application/x-archive":                   "application/x-unix-archive",
+	"application/x-redhat-package-manager":    "application/x-rpm",
+	"application/x-chrome-extension":          "application/x-google-chrome-extension",
+	"text/rtf":                                "application/rtf",
+	"image/jpg":                               "image/jpeg",
+	"image/pjpeg":                             "image/jpeg",
+	"image/x-icon":                            "image/vnd.microsoft.icon",
+	"image/x-photoshop":                       "image/vnd.adobe.photoshop",
+	"audio/mp3":                               "audio/mpeg",
+	"audio/flac":                              "audio/x-flac",
+	"video/webm":                              "video/x-matroska",
+	"audio/webm":                              "video/x-matroska",
+	"font/woff":                               "application/font-woff",
+	"application/x-font-woff":                 "application/font-woff",
+	"font/ttf":                                "application/font-sfnt",
+	"font/sfnt":                               "application/font-sfnt",
+	"application/x-font-ttf":                  "application/font-sfnt",
+}
+
+// Map a declared type to the type of the signatures sharing its magic number.
+func signatureType(contentType string) string {
+	contentType = strings.ToLower(contentType)
+	if strings.HasPrefix(contentType, "application/vnd.openxmlformats-officedocument.") ||
+		strings.HasPrefix(contentType, "application/vnd.oasis.opendocument.") {
+		return "application/zip"
+	}
+	if alias, ok := signatureAliases[contentType]; ok {
+		return alias
+	}
+	return contentType
+}
+
+// Report whether a content type holds text, JSON, JavaScript and XML included.
+func isTextual(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.HasPrefix(contentType, "text/") || strings.Contains(contentType, "json") ||
+		strings.Contains(contentType, "javascript") || strings.Contains(contentType, "ecmascript") ||
+		strings.Contains(contentType, "xml")
+}
+
+// Report whether the detected type of a body contradicts its declared type.
+// A magic number must match another type than the declared one, the generic
+// application/octet-stream matching any binary type. The types detected by
+// the standard library sniffer, which sees JSON, JavaScript or CSS as plain
+// text, only contradict a declared type of the other family, text or binary.
+func typeMismatch(detected string, magic bool, declared string) bool {
+	switch {
+	case detected == "":
+		return false
+	case magic && strings.EqualFold(declared, "application/octet-stream"):
+		return false
+	case magic:
+		return signatureType(detected) != signatureType(declared)
+	default:
+		return isTextual(detected) != isTextual(declared)
+	}
+}
@@ -0,0 +1,95 @@
+package inspector
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var titleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// Fingerprint of the response a host sends for a path that does not exist.
+type soft404Baseline struct {
+	statusCode int
+	words      int64
+	title      string
+}
+
+// Per-host soft-404 baselines, each host is calibrated once.
+type soft404Calibrator struct {
+	mu        sync.Mutex
+	baselines map[string]*soft404Calibration
+}
+
+type soft404Calibration struct {
+	once     sync.Once
+	baseline *soft404Baseline // nil when the host answers real 404s
+}
+
+// Return the soft-404 baseline of the host of target, requesting a random
+// nonexistent path on first use. Nil is returned for hosts answering 404.
+func (r *Runner) soft404Baseline(ctx context.Context, target *url.URL) *soft404Baseline {
+	key := target.Scheme + "://" + target.Host
+
+	c := &r.soft404
+	c.mu.Lock()
+	if c.baselines == nil {
+		c.baselines = make(map[string]*soft404Calibration)
+	}
+	calibration, ok := c.baselines[key]
+	if !ok {
+		calibration = &soft404Calibration{}
+		c.baselines[key] = calibration
+	}
+	c.mu.Unlock()
+
+	calibration.once.Do(func() {
+		calibration.baseline = r.calibrateSoft404(ctx, key)
+	})
+	return calibration.baseline
+}
+
+// Fingerprint the response to a random path of the given origin.
+func (r *Runner) calibrateSoft404(ctx context.Context, origin string) *soft404Baseline {
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return nil
+	}
+
+	resp, err := r.doRequest(ctx, "GET", origin+"/"+hex.EncodeToString(random))
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	body := readDecoded(resp, r.options.MaxBodySize, nil)
+	words, _ := countWordsLines(body)
+	return &soft404Baseline{statusCode: resp.StatusCode, words: words, title: htmlTitle(body)}
+}
+
+// Report whether a response looks like the baseline: same status code and
+// either the same title or the same word count, as the requested path is
+// often echoed in the page.
+func (b *soft404Baseline) matches(statusCode int, words int64, title string) bool {
+	if b == nil || statusCode != b.statusCode {
+		return false
+	}
+	return (title != "" && title == b.title) || words == b.words
+}
+
+// Extract the title of an HTML page.
+func htmlTitle(body []byte) string {
+	match := titleRegex.FindSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(match[1])), " ")
+}
@@ -0,0 +1,58 @@
+package inspector
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Load the local IPs the connections are bound to, from the source IPs or
+// the addresses of the interface, nil to let the system choose.
+func loadSourceIPs(options *Options) ([]net.IP, error) {
+	if len(options.SourceIPs) > 0 && options.Interface != "" {
+		return nil, fmt.Errorf("source IPs and interface can't be set together")
+	}
+
+	var ips []net.IP
+	for _, value := range options.SourceIPs {
+		ip := net.ParseIP(strings.TrimSpace(value))
+		if ip == nil {
+			return nil, fmt.Errorf("invalid source IP %s", value)
+		}
+		ips = append(ips, ip)
+	}
+	if options.Interface == "" {
+		return ips, nil
+	}
+
+	iface, err := net.InterfaceByName(options.Interface)
+	if err != nil {
+		return nil, fmt.Errorf("interface %s: %w", options.Interface, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("interface %s: %w", options.Interface, err)
+	}
+	// Bind the addresses of a single family, the remote addresses being
+	// picked in the family of the local one: IPv4 unless IPv6 only.
+	var v4, v6 []net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			v4 = append(v4, ipNet.IP)
+		} else {
+			v6 = append(v6, ipNet.IP)
+		}
+	}
+	ips = v4
+	if options.IPv6Only || (len(v4) == 0 && !options.IPv4Only) {
+		ips = v6
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("interface %s has no usable address", options.Interface)
+	}
+	return ips, nil
+}
@@ -0,0 +1,99 @@
+package inspector
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats summarizes a scan.
+type Stats struct {
+	Total       int64
+	Matched     int64
+	Errors      int64
+	Skipped     int64
+	Bytes       int64
+	StatusCodes map[int64]int64
+	Suffixes    map[string]int64
+	Elapsed     time.Duration
+}
+
+// RPS returns the average number of URLs inspected per second.
+func (s Stats) RPS() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Total) / s.Elapsed.Seconds()
+}
+
+// Counters updated by the workers and snapshotted by Runner.Stats.
+type statsCollector struct {
+	mu    sync.Mutex
+	start time.Time
+	stats Stats
+}
+
+// Start the elapsed time clock, only the first call has an effect.
+func (c *statsCollector) begin() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.start.IsZero() {
+		c.start = time.Now()
+	}
+}
+
+// Record the outcome of an inspected result.
+func (c *statsCollector) record(result *Result, matched bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats.StatusCodes == nil {
+		c.stats.StatusCodes = make(map[int64]int64)
+		c.stats.Suffixes = make(map[string]int64)
+	}
+	if result.Err != nil {
+		c.stats.Errors++
+		return
+	}
+	if matched {
+		c.stats.Matched++
+	}
+	if result.Data.StatusCode != 0 {
+		c.stats.StatusCodes[result.Data.StatusCode]++
+	}
+	if result.Data.Suffix != "" {
+		c.stats.Suffixes[result.Data.Suffix]++
+	}
+	if result.Data.BodySize > 0 {
+		c.stats.Bytes += result.Data.BodySize
+	} else if result.Data.ContentLength > 0 {
+		c.stats.Bytes += result.Data.ContentLength
+	}
+}
+
+// Record a URL skipped without being inspected.
+func (c *statsCollector) skip() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Skipped++
+}
+
+// Return a copy of the current statistics.
+func (c *statsCollector) snapshot(total int64) Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Total = total
+	stats.StatusCodes = make(map[int64]int64, len(c.stats.StatusCodes))
+	for code, count := range c.stats.StatusCodes {
+		stats.StatusCodes[code] = count
+	}
+	stats.Suffixes = make(map[string]int64, len(c.stats.Suffixes))
+	for suffix, count := range c.stats.Suffixes {
+		stats.Suffixes[suffix] = count
+	}
+	if !c.start.IsZero() {
+		stats.Elapsed = time.Since(c.start)
+	}
+	return stats
+}
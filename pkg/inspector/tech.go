@@ -0,0 +1,97 @@
+package inspector
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// A technology recognized from response headers, cookie names or the body.
+type techFingerprint struct {
+	name    string
+	headers map[string]*regexp.Regexp
+	cookies []string
+	body    *regexp.Regexp
+}
+
+// Known framework and server fingerprints. Header names are canonical.
+var techFingerprints = []techFingerprint{
+	{name: "WordPress", headers: map[string]*regexp.Regexp{"Link": regexp.MustCompile(`wp-json`)}, body: regexp.MustCompile(`/wp-(?:content|includes)/`)},
+	{name: "Drupal", headers: map[string]*regexp.Regexp{"X-Generator": regexp.MustCompile(`(?i)drupal`), "X-Drupal-Cache": regexp.MustCompile(`.`)}, body: regexp.MustCompile(`(?i)drupal-settings-json|sites/all/`)},
+	{name: "Joomla", body: regexp.MustCompile(`(?i)/media/jui/|content="Joomla`)},
+	{name: "Magento", headers: map[string]*regexp.Regexp{"X-Magento-Tags": regexp.MustCompile(`.`)}, body: regexp.MustCompile(`Mage\.Cookies|/static/version\d+/frontend/`)},
+	{name: "Shopify", headers: map[string]*regexp.Regexp{"X-Shopid": regexp.MustCompile(`.`)}, body: regexp.MustCompile(`cdn\.shopify\.com`)},
+	{name: "Laravel", cookies: []string{"laravel_session"}},
+	{name: "Django", cookies: []string{"csrftoken", "django_language"}, body: regexp.MustCompile(`csrfmiddlewaretoken`)},
+	{name: "Ruby on Rails", headers: map[string]*regexp.Regexp{"X-Runtime": regexp.MustCompile(`^[\d.]+$`)}, cookies: []string{"_rails_session"}},
+	{name: "Spring", headers: map[string]*regexp.Regexp{"X-Application-Context": regexp.MustCompile(`.`)}, body: regexp.MustCompile(`Whitelabel Error Page`)},
+	{name: "Apache Tomcat", body: regexp.MustCompile(`Apache Tomcat/\d`)},
+	{name: "ASP.NET", headers: map[string]*regexp.Regexp{"X-Aspnet-Version": regexp.MustCompile(`.`), "X-Powered-By": regexp.MustCompile(`ASP\.NET`)}, cookies: []string{"ASP.NET_SessionId", "ASPSESSIONID"}},
+	{name: "PHP", headers: map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`PHP`)}, cookies: []string{"PHPSESSID"}},
+	{name: "Java", cookies: []string{"JSESSIONID"}},
+	{name: "Express", headers: map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`Express`)}},
+	{name: "Next.js", headers: map[string]*regexp.Regexp{"X-Powered-By": regexp.MustCompile(`Next\.js`)}, body: regexp.MustCompile(`__NEXT_DATA__`)},
+	{name: "Nuxt.js", body: regexp.MustCompile(`window\.__NUXT__`)},
+	{name: "Jenkins", headers: map[string]*regexp.Regexp{"X-Jenkins": regexp.MustCompile(`.`)}},
+	{name: "GitLab", cookies: []string{"_gitlab_session"}},
+	{name: "Grafana", cookies: []string{"grafana_session"}},
+	{name: "Nginx", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)nginx`)}},
+	{name: "Apache", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)^apache`)}},
+	{name: "IIS", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)microsoft-iis`)}},
+	{name: "Cloudflare", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)cloudflare`), "Cf-Ray": regexp.MustCompile(`.`)}},
+	{name: "Varnish", headers: map[string]*regexp.Regexp{"Via": regexp.MustCompile(`(?i)varnish`), "X-Varnish": regexp.MustCompile(`.`)}},
+	{name: "Amazon S3", headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`AmazonS3`)}},
+}
+
+// Detect the technologies of a response. The raw Server and X-Powered-By
+// values are reported as they often carry version numbers.
+func detectTechnologies(header http.Header, body []byte) []string {
+	seen := make(map[string]bool)
+	var technologies []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			technologies = append(technologies, name)
+		}
+	}
+
+	add(header.Get("Server"))
+	add(header.Get("X-Powered-By"))
+
+	cookies := make(map[string]bool)
+	for _, cookie := range (&http.Response{Header: header}).Cookies() {
+		cookies[cookie.Name] = true
+	}
+
+	var fingerprinted []string
+	for _, fp := range techFingerprints {
+		if fp.matches(header, cookies, body) {
+			fingerprinted = append(fingerprinted, fp.name)
+		}
+	}
+	sort.Strings(fingerprinted)
+	for _, name := range fingerprinted {
+		add(name)
+	}
+	return technologies
+}
+
+// Report whether any of the header, cookie or body hints is present.
+func (fp techFingerprint) matches(header http.Header, cookies map[string]bool, body []byte) bool {
+	for name, re := range fp.headers {
+		for _, value := range header.Values(name) {
+			if re.MatchString(value) {
+				return true
+			}
+		}
+	}
+	for name := range cookies {
+		for _, cookie := range fp.cookies {
+			if strings.HasPrefix(name, cookie) {
+				return true
+			}
+		}
+	}
+	return fp.body != nil && len(body) > 0 && fp.body.Match(body)
+}
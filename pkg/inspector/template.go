@@ -0,0 +1,71 @@
+package inspector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Placeholders of URL templates: {{word}} and FUZZ take every word of the
+// wordlist, {{1-100}} every number of the range.
+var placeholderRegex = regexp.MustCompile(`\{\{\s*(word|\d+\s*-\s*\d+)\s*\}\}|FUZZ`)
+
+// Max number of values of a numeric range placeholder.
+const maxTemplateRange = 1000000
+
+// Report whether an input URL is a template with placeholders.
+func isTemplate(target string) bool {
+	return placeholderRegex.MatchString(target)
+}
+
+// Generate the URLs of a template, one per combination of the values of its
+// placeholders, calling emit for each one.
+func expandTemplate(template string, words []string, emit func(string)) error {
+	loc := placeholderRegex.FindStringSubmatchIndex(template)
+	if loc == nil {
+		emit(template)
+		return nil
+	}
+
+	values, err := placeholderValues(template[loc[0]:loc[1]], words)
+	if err != nil {
+		return err
+	}
+	prefix, rest := template[:loc[0]], template[loc[1]:]
+	// Values are never expanded themselves, only the rest of the template is.
+	return expandTemplate(rest, words, func(suffix string) {
+		for _, value := range values {
+			emit(prefix + value + suffix)
+		}
+	})
+}
+
+// Return the values of a placeholder: the words of the wordlist, or the
+// numbers of a range, zero padded when its start is (001-100).
+func placeholderValues(placeholder string, words []string) ([]string, error) {
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(placeholder, "{{"), "}}"))
+	if placeholder == "FUZZ" || inner == "word" {
+		if len(words) == 0 {
+			return nil, fmt.Errorf("%w: the %s placeholder needs a wordlist", ErrInvalidURL, placeholder)
+		}
+		return words, nil
+	}
+
+	low, high, _ := strings.Cut(inner, "-")
+	low, high = strings.TrimSpace(low), strings.TrimSpace(high)
+	first, err1 := strconv.Atoi(low)
+	last, err2 := strconv.Atoi(high)
+	if err1 != nil || err2 != nil || first > last || last-first >= maxTemplateRange {
+		return nil, fmt.Errorf("%w: invalid range %s", ErrInvalidURL, placeholder)
+	}
+	width := 0
+	if len(low) > 1 && low[0] == '0' {
+		width = len(low)
+	}
+	values := make([]string, 0, last-first+1)
+	for n := first; n <= last; n++ {
+		values = append(values, fmt.Sprintf("%0*d", width, n))
+	}
+	return values, nil
+}
@@ -0,0 +1,130 @@
+package inspector
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	throttleMinDelay = 250 * time.Millisecond // Delay between the requests of a host once throttled.
+	throttleMaxDelay = 30 * time.Second
+	throttleRecovery = 10 // Successful responses halving the delay of a throttled host.
+)
+
+// Slows down the hosts answering with timeouts, 429 or 503 responses by
+// spacing their requests, the delay doubling on each of these answers and
+// halving back after a series of successful ones, down to full speed again.
+// Other hosts keep their full speed.
+type hostThrottle struct {
+	mu    sync.Mutex
+	hosts map[string]*throttleState
+}
+
+// Throttling of a host that answered as overloaded at least once.
+type throttleState struct {
+	delay     time.Duration // Zero once back at full speed.
+	next      time.Time     // Earliest time of the next request.
+	successes int           // Successful responses since the delay last changed.
+	raised    time.Time     // Last time the delay was raised.
+}
+
+// Create the throttle of the hosts, nil when disabled.
+func newHostThrottle(enabled bool) *hostThrottle {
+	if !enabled {
+		return nil
+	}
+	return &hostThrottle{hosts: make(map[string]*throttleState)}
+}
+
+// Block until host may be requested again or the context is done.
+func (t *hostThrottle) Wait(ctx context.Context, host string) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	state := t.hosts[host]
+	if state == nil || (state.delay == 0 && !state.next.After(time.Now())) {
+		t.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	if state.next.Before(now) {
+		state.next = now
+	}
+	wait := state.next.Sub(now)
+	state.next = state.next.Add(state.delay)
+	t.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Adjust the delay of host to the outcome of a request to it.
+func (t *hostThrottle) observe(host string, resp *http.Response, err error) {
+	if t == nil {
+		return
+	}
+	var overloaded bool
+	var retryAfter time.Duration
+	if err != nil {
+		// Other errors say nothing about the load of the host.
+		if ErrorType(err) != ErrorTypeTimeout {
+			return
+		}
+		overloaded = true
+	} else if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		overloaded = true
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = min(time.Duration(seconds)*time.Second, throttleMaxDelay)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.hosts[host]
+	if state == nil {
+		if !overloaded {
+			return
+		}
+		state = &throttleState{}
+		t.hosts[host] = state
+	}
+
+	if overloaded {
+		// The concurrent requests of a burst raise the delay once.
+		now := time.Now()
+		if now.Sub(state.raised) >= state.delay {
+			state.delay = min(max(2*state.delay, throttleMinDelay), throttleMaxDelay)
+			state.raised = now
+		}
+		state.successes = 0
+		if next := now.Add(retryAfter); next.After(state.next) {
+			state.next = next
+		}
+		return
+	}
+	if state.delay == 0 {
+		return
+	}
+	state.successes++
+	if state.successes >= throttleRecovery {
+		state.delay /= 2
+		state.successes = 0
+		if state.delay < throttleMinDelay {
+			state.delay = 0
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package inspector
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// TLS versions by name, with or without the "tls" prefix.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Build the TLS config of the transport: certificate verification, server
+// name, client certificate, version range and cipher suites.
+func buildTLSConfig(options *Options) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: options.Insecure, ServerName: options.SNI}
+
+	// The SNI follows the Host header override so certificates are verified
+	// against the virtual host rather than the requested IP.
+	if config.ServerName == "" && options.HostHeader != "" {
+		config.ServerName = options.HostHeader
+		if host, _, err := net.SplitHostPort(options.HostHeader); err == nil {
+			config.ServerName = host
+		}
+	}
+
+	if options.ClientKey != "" && options.ClientCert == "" {
+		return nil, fmt.Errorf("a client key needs a client certificate")
+	}
+	if options.ClientCert != "" {
+		// The key may be stored in the certificate file.
+		keyFile := options.ClientKey
+		if keyFile == "" {
+			keyFile = options.ClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(options.ClientCert, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate %s: %w", options.ClientCert, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	var err error
+	if config.MinVersion, err = parseTLSVersion(options.TLSMinVersion); err != nil {
+		return nil, err
+	}
+	if config.MaxVersion, err = parseTLSVersion(options.TLSMaxVersion); err != nil {
+		return nil, err
+	}
+	if config.MaxVersion != 0 && config.MinVersion > config.MaxVersion {
+		return nil, fmt.Errorf("TLS min version %s is above max version %s", options.TLSMinVersion, options.TLSMaxVersion)
+	}
+
+	if config.CipherSuites, err = parseCipherSuites(options.TLSCiphers); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Parse a TLS version such as 1.2 or tls1.2, 0 when empty.
+func parseTLSVersion(value string) (uint16, error) {
+	if value == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(value), "tls")]
+	if !ok {
+		return 0, fmt.Errorf("invalid TLS version %q, use 1.0, 1.1, 1.2 or 1.3", value)
+	}
+	return version, nil
+}
+
+// Parse a comma separated list of cipher suite names, including the
+// insecure ones needed by legacy servers. TLS 1.3 suites are not
+// configurable and are ignored by the TLS stack.
+func parseCipherSuites(value string) ([]uint16, error) {
+	if value == "" {
+		return nil, nil
+	}
+	suites := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites[suite.Name] = suite.ID
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		id, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
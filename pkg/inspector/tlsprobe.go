@@ -0,0 +1,57 @@
+package inspector
+
+import (
+	"crypto/tls"
+	"strings"
+	"time"
+)
+
+// TLSInfo holds the negotiated TLS parameters and the leaf certificate details.
+type TLSInfo struct {
+	Version    string    `json:"version"`
+	Cipher     string    `json:"cipher"`
+	SubjectCN  string    `json:"subject_cn,omitempty"`
+	Subject    string    `json:"subject,omitempty"`
+	Issuer     string    `json:"issuer,omitempty"`
+	SANs       []string  `json:"sans,omitempty"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+	Expired    bool      `json:"expired,omitempty"`
+	Wildcard   bool      `json:"wildcard,omitempty"`
+	SelfSigned bool      `json:"self_signed,omitempty"`
+}
+
+// Extract the TLS details of a connection, nil for plain HTTP.
+func tlsInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &TLSInfo{
+		Version: tls.VersionName(state.Version),
+		Cipher:  tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) == 0 {
+		return info
+	}
+
+	cert := state.PeerCertificates[0]
+	info.SubjectCN = cert.Subject.CommonName
+	info.Subject = cert.Subject.String()
+	info.Issuer = cert.Issuer.String()
+	info.NotBefore = cert.NotBefore
+	info.NotAfter = cert.NotAfter
+	info.Expired = time.Now().After(cert.NotAfter)
+	info.SelfSigned = cert.Subject.String() == cert.Issuer.String()
+
+	info.SANs = append(info.SANs, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+	for _, name := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+		if strings.HasPrefix(name, "*.") {
+			info.Wildcard = true
+		}
+	}
+	return info
+}
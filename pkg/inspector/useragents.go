@@ -0,0 +1,74 @@
+package inspector
+
+import (
+	_ "embed"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+)
+
+//go:embed mappings/user-agents.txt
+var builtinUserAgents string
+
+// Named User-Agents accepted in place of a UserAgent value.
+var userAgentPresets = map[string]string{
+	"chrome":    "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/127.0.0.0 Safari/537.36",
+	"firefox":   "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:128.0) Gecko/20100101 Firefox/128.0",
+	"safari":    "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15",
+	"edge":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/127.0.0.0 Safari/537.36 Edg/127.0.0.0",
+	"mobile":    "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+	"android":   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/127.0.0.0 Mobile Safari/537.36",
+	"googlebot": "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+	"bingbot":   "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+	"curl":      "curl/8.8.0",
+}
+
+// UserAgentPresets returns the names of the User-Agent presets.
+func UserAgentPresets() []string {
+	names := make([]string, 0, len(userAgentPresets))
+	for name := range userAgentPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve a UserAgent value naming a preset to its User-Agent, other values
+// being used as is.
+func resolveUserAgent(value string) string {
+	if preset, ok := userAgentPresets[strings.ToLower(value)]; ok {
+		return preset
+	}
+	return value
+}
+
+// Load the User-Agents rotated across the requests, from the file or the
+// built-in list with RandomAgent. It returns nil when not rotating.
+func loadUserAgents(options *Options) ([]string, error) {
+	if options.UserAgentFile != "" {
+		agents, err := readWordlist(options.UserAgentFile, "User-Agent file")
+		if err == nil && len(agents) == 0 {
+			err = fmt.Errorf("User-Agent file %s has no User-Agent", options.UserAgentFile)
+		}
+		return agents, err
+	}
+	if !options.RandomAgent {
+		return nil, nil
+	}
+	var agents []string
+	for _, line := range strings.Split(builtinUserAgents, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			agents = append(agents, line)
+		}
+	}
+	return agents, nil
+}
+
+// Return the User-Agent of a request, a random one when rotating.
+func (r *Runner) userAgent() string {
+	if len(r.userAgents) > 0 {
+		return r.userAgents[rand.IntN(len(r.userAgents))]
+	}
+	return r.options.UserAgent
+}
@@ -0,0 +1,139 @@
+package inspector
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Bytes fetched per range request, the central directory is read in
+	// small chunks by archive/zip.
+	zipPeekBlockSize = 64 * 1024
+
+	// Max number of file names listed per archive.
+	maxZipEntries = 1000
+)
+
+// errRangeUnsupported is returned when a server ignores Range requests.
+var errRangeUnsupported = errors.New("range requests not supported")
+
+// Report whether a result is a ZIP archive, from its suffix, sniffed type or URL.
+func isZip(result *Result) bool {
+	if result.Data.Suffix == "zip" || result.Data.DetectedType == "application/zip" {
+		return true
+	}
+	parsed, err := url.Parse(result.Host)
+	return err == nil && strings.EqualFold(path.Ext(parsed.Path), ".zip")
+}
+
+// List the files of a remote ZIP archive reading only its central directory
+// through Range requests. The total number of entries is also returned.
+func (r *Runner) peekZip(ctx context.Context, target string, size int64) ([]string, int, error) {
+	reader := &rangeReader{ctx: ctx, runner: r, target: target}
+	if size <= 0 {
+		var err error
+		if size, err = reader.size(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	archive, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	var names []string
+	for _, file := range archive.File[:min(len(archive.File), maxZipEntries)] {
+		names = append(names, file.Name)
+	}
+	return names, len(archive.File), nil
+}
+
+// An io.ReaderAt over a remote file, fetching blocks with Range requests.
+type rangeReader struct {
+	ctx    context.Context
+	runner *Runner
+	target string
+
+	// Last fetched block.
+	offset int64
+	block  []byte
+}
+
+// Report whether the server honors Range requests for target, from the
+// Accept-Ranges header of resp or else by requesting its first byte.
+func (r *Runner) rangeSupported(ctx context.Context, target string, resp *http.Response) bool {
+	switch strings.ToLower(resp.Header.Get("Accept-Ranges")) {
+	case "bytes":
+		return true
+	case "none":
+		return false
+	}
+	rangeResp, err := (&rangeReader{ctx: ctx, runner: r, target: target}).fetch("bytes=0-0")
+	if err != nil {
+		return false
+	}
+	rangeResp.Body.Close()
+	return true
+}
+
+// Fetch a byte range, spec being the value of the Range header.
+func (rr *rangeReader) fetch(spec string) (*http.Response, error) {
+	resp, _, err := rr.runner.tracedRequest(rr.ctx, "GET", rr.target, http.Header{"Range": {spec}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, errRangeUnsupported
+	}
+	return resp, nil
+}
+
+// Get the file size from the Content-Range of a one byte request.
+func (rr *rangeReader) size() (int64, error) {
+	resp, err := rr.fetch("bytes=0-0")
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	_, total, _ := strings.Cut(resp.Header.Get("Content-Range"), "/")
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unknown size in Content-Range %q", resp.Header.Get("Content-Range"))
+	}
+	return size, nil
+}
+
+func (rr *rangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	end := off + int64(len(p))
+	if off < rr.offset || end > rr.offset+int64(len(rr.block)) {
+		resp, err := rr.fetch(fmt.Sprintf("bytes=%d-%d", off, off+max(int64(len(p)), zipPeekBlockSize)-1))
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		block, err := io.ReadAll(io.LimitReader(resp.Body, max(int64(len(p)), zipPeekBlockSize)))
+		if err != nil {
+			return 0, err
+		}
+		rr.offset, rr.block = off, block
+	}
+
+	n := copy(p, rr.block[off-rr.offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+)
+
+// Start the profiling asked by the options, the pprof listener and the CPU
+// profile, and return the function stopping it once the scan is over, which
+// also writes the memory profile.
+func startProfiling(options *Options) (func(), error) {
+	var server *http.Server
+	if options.PprofAddr != "" {
+		listener, err := net.Listen("tcp", options.PprofAddr)
+		if err != nil {
+			return nil, fmt.Errorf("serving pprof: %w", err)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("serving pprof", "addr", options.PprofAddr, "error", err)
+			}
+		}()
+		logger.Info("serving pprof", "url", "http://"+listener.Addr().String()+"/debug/pprof/")
+	}
+
+	var cpuFile *os.File
+	if options.CPUProfile != "" {
+		var err error
+		if cpuFile, err = os.Create(options.CPUProfile); err != nil {
+			if server != nil {
+				server.Close()
+			}
+			return nil, fmt.Errorf("creating CPU profile: %w", err)
+		}
+		if err := runtimepprof.StartCPUProfile(cpuFile); err != nil {
+			cpuFile.Close()
+			if server != nil {
+				server.Close()
+			}
+			return nil, fmt.Errorf("starting CPU profile: %w", err)
+		}
+	}
+
+	return func() {
+		if cpuFile != nil {
+			runtimepprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				logger.Error("writing CPU profile", "file", options.CPUProfile, "error", err)
+			}
+		}
+		if options.MemProfile != "" {
+			if err := writeMemProfile(options.MemProfile); err != nil {
+				logger.Error("writing memory profile", "file", options.MemProfile, "error", err)
+			}
+		}
+		if server != nil {
+			server.Close()
+		}
+	}, nil
+}
+
+// Write the heap profile, after a garbage collection so it reflects the
+// memory still in use.
+func writeMemProfile(fileName string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	runtime.GC()
+	if err := runtimepprof.WriteHeapProfile(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/goflags"
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in scan presets, mapping long flag names to their values.
+var builtinProfiles = map[string]map[string]interface{}{
+	"backups": {
+		"match-code":   "200-299",
+		"match-suffix": "zip,tar,rar,gz,bz2,7z,xz,zstd,lz,Z,targz,tarbz2,tarxz,tarzst,sql,SQL,sqlgz,sqlbz2,sqlxz,sqlzip,sql7z,sqlite,env,interesting",
+		"sniff":        true,
+	},
+	"js-files": {
+		"match-code": "200-299",
+		"match-type": "application/javascript,text/javascript,application/x-javascript,application/ecmascript",
+	},
+	"documents": {
+		"match-code":   "200-299",
+		"match-suffix": "pdf,doc,docx,xls,xlsx,ppt,pptx,rtf,epub",
+	},
+}
+
+// Apply the flag values of a profile, either built-in or defined under the
+// "profiles" key of the config file. Flags given on the command line take
+// precedence over the profile, which takes precedence over the config file.
+func applyProfile(flagSet *goflags.FlagSet, name string, configFile string) error {
+	profiles, err := loadProfiles(configFile)
+	if err != nil {
+		return err
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for profileName := range profiles {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown profile %q, available profiles: %s", name, strings.Join(names, ", "))
+	}
+
+	// Visit only walks the flags set on the command line. The long and short
+	// names of a flag are distinct flags sharing the same value.
+	setOnCommandLine := make(map[flag.Value]bool)
+	flagSet.CommandLine.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Value] = true
+	})
+
+	for flagName, value := range profile {
+		f := flagSet.CommandLine.Lookup(flagName)
+		if f == nil {
+			return fmt.Errorf("unknown flag %q in profile %s", flagName, name)
+		}
+		if setOnCommandLine[f.Value] {
+			continue
+		}
+
+		values := []interface{}{value}
+		if list, ok := value.([]interface{}); ok {
+			values = list
+		}
+		for _, v := range values {
+			if err := f.Value.Set(fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("invalid value %v for flag %q in profile %s: %w", v, flagName, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Merge the built-in profiles with the ones of the config file, user profiles
+// replace built-in ones with the same name.
+func loadProfiles(configFile string) (map[string]map[string]interface{}, error) {
+	profiles := make(map[string]map[string]interface{}, len(builtinProfiles))
+	for name, profile := range builtinProfiles {
+		profiles[name] = profile
+	}
+
+	data, err := os.ReadFile(configFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config file %s: %w", configFile, err)
+	}
+
+	var config struct {
+		Profiles map[string]map[string]interface{} `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", configFile, err)
+	}
+	for name, profile := range config.Profiles {
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Width of the progress bar in characters
+const progressBarWidth = 30
+
+// Count the URLs of the input file in the shard to know the scan size.
+func countTargets(options *Options) (int64, error) {
+	file, err := openInput(context.Background(), options.InputFile)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var count int64
+	err = extractURLs(options.InputFormat, file, options.MaxURLLength, func(request inspector.Request) bool {
+		if options.shard.includes(request.URL) {
+			count++
+		}
+		return true
+	}, nil)
+	return count, err
+}
+
+// Render a progress bar on stderr until done is closed.
+func showProgress(runner *inspector.Runner, total int64, done <-chan struct{}) {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	var rps float64
+	var lastCount int64
+	lastTime := time.Now()
+	for {
+		select {
+		case <-done:
+			fmt.Fprintf(os.Stderr, "\r%s\n", progressLine(runner.Stats(), total, rps))
+			return
+		case now := <-ticker.C:
+			stats := runner.Stats()
+			// Current rate over the last second rather than the whole scan
+			if elapsed := now.Sub(lastTime); elapsed >= time.Second {
+				rps = float64(stats.Total-lastCount) / elapsed.Seconds()
+				lastCount, lastTime = stats.Total, now
+			}
+			fmt.Fprintf(os.Stderr, "\r%s", progressLine(stats, total, rps))
+		}
+	}
+}
+
+// Format a single progress bar line.
+func progressLine(stats inspector.Stats, total int64, rps float64) string {
+	if total <= 0 {
+		total = 1
+	}
+	completed := min(stats.Total, total)
+	percent := float64(completed) / float64(total) * 100
+	filled := int(percent / 100 * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+
+	// The ETA uses the average rate which is steadier than the current one
+	eta := "-"
+	if completed == total {
+		eta = "0s"
+	} else if average := stats.RPS(); average > 0 {
+		eta = time.Duration(float64(total-completed) / average * float64(time.Second)).Round(time.Second).String()
+	}
+	return fmt.Sprintf("[%s] %3.0f%% %d/%d %.1f req/s ETA %s   ", bar, percent, completed, total, rps, eta)
+}
+
+// Print the statistics of the scan on stderr at every interval, until the
+// returned function is called.
+func printStatsEvery(runner *inspector.Runner, interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	// Stopping the ticker doesn't close its channel, done does.
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				stats := runner.Stats()
+				fmt.Fprintf(os.Stderr, "[stats] %d URLs, %d matched, %d errors, %.2f req/s, elapsed %s\n", stats.Total, stats.Matched, stats.Errors, stats.RPS(), stats.Elapsed.Round(time.Second))
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
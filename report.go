@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// A bar of the report charts.
+type reportBar struct {
+	Label   string
+	Count   int
+	Percent float64 // Width of the bar, relative to the largest one.
+}
+
+// A failed URL of the report.
+type reportError struct {
+	Host    string
+	Type    string
+	Message string
+}
+
+// Data rendered by the report templates.
+type reportData struct {
+	Generated   string
+	Stats       inspector.Stats
+	Scanned     bool // Whether the stats come from a scan, or only count the results.
+	Results     []*inspector.Result
+	Errors      []reportError
+	StatusCodes []reportBar
+	Suffixes    []reportBar
+}
+
+// Build the report of the results of a scan.
+func newReportData(results []*inspector.Result, stats inspector.Stats) *reportData {
+	data := &reportData{Stats: stats, Scanned: true}
+	for _, result := range results {
+		if result.Err != nil {
+			data.Errors = append(data.Errors, reportError{Host: result.Host, Type: inspector.ErrorType(result.Err), Message: result.Err.Error()})
+		} else {
+			data.Results = append(data.Results, result)
+		}
+	}
+	return data
+}
+
+// Build the report of saved JSON or JSONL results, the failed URLs included
+// with -include-errors being listed as errors.
+func loadReportData(input io.Reader) (*reportData, error) {
+	data := &reportData{}
+	decoder := json.NewDecoder(input)
+	for {
+		var record struct {
+			inspector.Result
+			Error     string `json:"error"`
+			ErrorType string `json:"error_type"`
+		}
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing results: %w", err)
+		}
+		if record.Error != "" {
+			data.Errors = append(data.Errors, reportError{Host: record.Host, Type: record.ErrorType, Message: record.Error})
+			continue
+		}
+		result := record.Result
+		data.Results = append(data.Results, &result)
+	}
+	data.Stats.Total = int64(len(data.Results) + len(data.Errors))
+	data.Stats.Matched = int64(len(data.Results))
+	data.Stats.Errors = int64(len(data.Errors))
+	return data, nil
+}
+
+// Count the values of the results into chart bars, the largest first.
+func reportBars(results []*inspector.Result, label func(*inspector.Result) string) []reportBar {
+	counts := make(map[string]int)
+	for _, result := range results {
+		if value := label(result); value != "" {
+			counts[value]++
+		}
+	}
+	bars := make([]reportBar, 0, len(counts))
+	largest := 0
+	for value, count := range counts {
+		bars = append(bars, reportBar{Label: value, Count: count})
+		largest = max(largest, count)
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Count != bars[j].Count {
+			return bars[i].Count > bars[j].Count
+		}
+		return bars[i].Label < bars[j].Label
+	})
+	for i := range bars {
+		bars[i].Percent = float64(bars[i].Count) / float64(largest) * 100
+	}
+	return bars
+}
+
+// Render a report as a single self-contained HTML page, without any external
+// stylesheet or script so it can be shared as is, or as Markdown.
+func renderReport(w io.Writer, data *reportData, format string) error {
+	data.Generated = time.Now().Format("2006-01-02 15:04:05")
+	data.StatusCodes = reportBars(data.Results, func(result *inspector.Result) string {
+		if result.Data.StatusCode == 0 {
+			return ""
+		}
+		return strconv.FormatInt(result.Data.StatusCode, 10)
+	})
+	data.Suffixes = reportBars(data.Results, func(result *inspector.Result) string {
+		return result.Data.Suffix
+	})
+
+	var err error
+	switch format {
+	case "html":
+		err = reportTemplate.Execute(w, data)
+	case "md":
+		err = markdownReportTemplate.Execute(w, data)
+	default:
+		return fmt.Errorf("invalid report format %q, use html or md", format)
+	}
+	if err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	return nil
+}
+
+// Report format of a file, Markdown for .md files and HTML otherwise.
+func reportFormat(fileName string) string {
+	if ext := strings.ToLower(filepath.Ext(fileName)); ext == ".md" || ext == ".markdown" {
+		return "md"
+	}
+	return "html"
+}
+
+// Write a report to a file, in the format of its extension.
+func writeReport(fileName string, data *reportData) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := renderReport(file, data, reportFormat(fileName)); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Escape the text of a Markdown table cell.
+func markdownCell(value any) string {
+	text := strings.ReplaceAll(fmt.Sprint(value), "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var markdownReportTemplate = template.Must(template.New("markdown").Funcs(template.FuncMap{
+	"cell": markdownCell,
+}).Parse(`# linkinspector report
+
+Generated {{.Generated}}
+
+| URLs | Matched | Errors |{{if .Scanned}} Skipped | Bytes | Requests/sec |{{end}}
+|---|---|---|{{if .Scanned}}---|---|---|{{end}}
+| {{.Stats.Total}} | {{.Stats.Matched}} | {{.Stats.Errors}} |{{if .Scanned}} {{.Stats.Skipped}} | {{.Stats.Bytes}} | {{printf "%.2f" .Stats.RPS}} |{{end}}
+
+## Status codes
+
+| Status | Count |
+|---|---|
+{{range .StatusCodes}}| {{.Label}} | {{.Count}} |
+{{end}}
+## Suffixes
+
+| Suffix | Count |
+|---|---|
+{{range .Suffixes}}| {{cell .Label}} | {{.Count}} |
+{{end}}
+## Results ({{len .Results}})
+
+| URL | Status | Length | Content type | Suffix | Source |
+|---|---|---|---|---|---|
+{{range .Results}}| {{cell .Host}} | {{if .Data.StatusCode}}{{.Data.StatusCode}}{{end}} | {{.Data.ContentLength}} | {{cell .Data.ContentType}} | {{cell .Data.Suffix}} | {{cell .Data.Source}} |
+{{end}}{{if .Errors}}
+## Errors ({{len .Errors}})
+
+| URL | Error type | Error |
+|---|---|---|
+{{range .Errors}}| {{cell .Host}} | {{.Type}} | {{cell .Message}} |
+{{end}}{{end}}`))
+
+var reportTemplate = htmltemplate.Must(htmltemplate.New("report").Funcs(htmltemplate.FuncMap{
+	// Color class of a status code, by its first digit.
+	"statusClass": func(code int64) string {
+		if code < 100 {
+			return ""
+		}
+		return "s" + strconv.FormatInt(code/100, 10)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>linkinspector report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0; }
+.generated { color: #777; margin-top: .3em; }
+.summary { display: flex; flex-wrap: wrap; gap: 1em; margin: 1.5em 0; }
+.summary div { background: #f3f5f8; border-radius: 6px; padding: .8em 1.2em; }
+.summary b { display: block; font-size: 1.4em; }
+.charts { display: flex; flex-wrap: wrap; gap: 3em; }
+.chart { min-width: 320px; }
+.bar { display: flex; align-items: center; margin: .25em 0; }
+.bar span { width: 9em; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.bar i { display: inline-block; height: 1.1em; background: #4a7bd0; margin-right: .5em; border-radius: 2px; }
+table { border-collapse: collapse; width: 100%; margin-top: 1em; font-size: .9em; }
+th, td { border-bottom: 1px solid #e3e6ea; padding: .4em .6em; text-align: left; vertical-align: top; }
+th { background: #f3f5f8; cursor: pointer; user-select: none; position: sticky; top: 0; }
+th:after { content: " \2195"; color: #aaa; }
+td.url { word-break: break-all; }
+.s2 { color: #1f8a3b; } .s3 { color: #2c6fbf; } .s4 { color: #c57a00; } .s5 { color: #c0392b; }
+</style>
+</head>
+<body>
+<h1>linkinspector report</h1>
+<p class="generated">Generated {{.Generated}}</p>
+
+<div class="summary">
+<div><b>{{.Stats.Total}}</b>URLs</div>
+<div><b>{{.Stats.Matched}}</b>Matched</div>
+<div><b>{{.Stats.Errors}}</b>Errors</div>
+{{if .Scanned}}<div><b>{{.Stats.Skipped}}</b>Skipped</div>
+<div><b>{{.Stats.Bytes}}</b>Bytes</div>
+<div><b>{{printf "%.2f" .Stats.RPS}}</b>Requests/sec</div>{{end}}
+</div>
+
+<div class="charts">
+<div class="chart">
+<h2>Status codes</h2>
+{{range .StatusCodes}}<div class="bar"><span>{{.Label}}</span><i style="width: {{printf "%.0f" .Percent}}%; max-width: 20em"></i>{{.Count}}</div>
+{{else}}<p>No results.</p>
+{{end}}</div>
+<div class="chart">
+<h2>Suffixes</h2>
+{{range .Suffixes}}<div class="bar"><span>{{.Label}}</span><i style="width: {{printf "%.0f" .Percent}}%; max-width: 20em"></i>{{.Count}}</div>
+{{else}}<p>No results.</p>
+{{end}}</div>
+</div>
+
+<h2>Results ({{len .Results}})</h2>
+<table class="sortable">
+<thead><tr><th>URL</th><th>Status</th><th>Length</th><th>Content type</th><th>Suffix</th><th>Time (ms)</th><th>Source</th></tr></thead>
+<tbody>
+{{range .Results}}<tr>
+<td class="url"><a href="{{.Host}}" target="_blank" rel="noopener noreferrer">{{.Host}}</a>{{if .Data.FinalURL}}<br>&rarr; {{.Data.FinalURL}}{{end}}</td>
+<td class="{{statusClass .Data.StatusCode}}">{{if .Data.StatusCode}}{{.Data.StatusCode}}{{end}}</td>
+<td data-value="{{.Data.ContentLength}}">{{.Data.ContentLength}}</td>
+<td>{{.Data.ContentType}}</td>
+<td>{{.Data.Suffix}}</td>
+<td data-value="{{.Data.ResponseTime}}">{{.Data.ResponseTime}}</td>
+<td class="url">{{.Data.Source}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{if .Errors}}
+<h2>Errors ({{len .Errors}})</h2>
+<table class="sortable">
+<thead><tr><th>URL</th><th>Error type</th><th>Error</th></tr></thead>
+<tbody>
+{{range .Errors}}<tr><td class="url">{{.Host}}</td><td>{{.Type}}</td><td>{{.Message}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+<script>
+// Sort a table by the clicked column, numerically when the cells are numbers.
+document.querySelectorAll("table.sortable th").forEach(function (th) {
+  th.addEventListener("click", function () {
+    var column = th.cellIndex, table = th.closest("table"), body = table.tBodies[0];
+    var ascending = th.dataset.order !== "asc";
+    table.querySelectorAll("th").forEach(function (other) { delete other.dataset.order; });
+    th.dataset.order = ascending ? "asc" : "desc";
+    var value = function (row) {
+      var cell = row.cells[column];
+      return cell.dataset.value !== undefined ? cell.dataset.value : cell.textContent.trim();
+    };
+    Array.from(body.rows).sort(function (a, b) {
+      var x = value(a), y = value(b);
+      var order = (x !== "" && y !== "" && !isNaN(x) && !isNaN(y)) ? x - y : x.localeCompare(y);
+      return ascending ? order : -order;
+    }).forEach(function (row) { body.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Check the options of the serve subcommand, whose input never ends while
+// listening.
+func validateServe(options *Options) error {
+	switch {
+	case options.Monitor:
+		return errors.New("the serve subcommand can't be used in monitor mode")
+	case options.InputFile != "" || options.InputTargetHost != "":
+		return errors.New("the serve subcommand takes its URLs over HTTP or stdin, not from -l or -u")
+	case options.Shuffle || options.ProgressBar || len(options.PassiveSources) > 0:
+		return errors.New("-shuffle, -progress-bar and -passive-sources need the whole input, they can't be used by the serve subcommand")
+	case options.MaxRuntime > 0 || options.MaxResults > 0:
+		return errors.New("-max-runtime and -max-results cut the scan short, they can't be used by the serve subcommand")
+	}
+	return nil
+}
+
+// Inspect the URLs POSTed to /scan of the -listen address until
+// interrupted, or the URLs read from stdin without it, passing the results
+// to deliver, and return the statistics of the run.
+func runServe(ctx context.Context, runner *inspector.Runner, options *Options, deliver func(*inspector.Result)) (inspector.Stats, error) {
+	options.pause.attach(runner)
+	defer options.pause.attach(nil)
+
+	// Only closed at the end of stdin, the runner stops at the interrupt.
+	requests := make(chan inspector.Request)
+	if options.Listen != "" {
+		server, err := startScanServer(ctx, options, requests)
+		if err != nil {
+			return inspector.Stats{}, fmt.Errorf("listening on %s: %w", options.Listen, err)
+		}
+		defer server.Close()
+	} else {
+		go func() {
+			defer close(requests)
+			if _, err := queueInput(ctx, options, options.InputFormat, os.Stdin, requests); err != nil {
+				logger.Error("reading input", "input", "stdin", "error", err)
+			}
+		}()
+	}
+
+	if options.StatsInterval > 0 {
+		defer printStatsEvery(runner, options.StatsInterval)()
+	}
+	for result := range runner.RunRequests(ctx, requests) {
+		deliver(result)
+	}
+
+	stats := runner.Stats()
+	if options.Stats {
+		printStats(stats)
+	}
+	if ctx.Err() != nil {
+		logger.Info("service stopped", "completed", runner.Processed())
+	}
+	return stats, nil
+}
+
+// Send the URLs of an input to the runner, skipping those of other shards,
+// and return how many were queued.
+func queueInput(ctx context.Context, options *Options, format string, input io.Reader, requests chan<- inspector.Request) (int, error) {
+	var queued int
+	err := extractURLs(format, input, options.MaxURLLength, func(request inspector.Request) bool {
+		if !options.shard.includes(request.URL) {
+			return true
+		}
+		select {
+		case requests <- request:
+			queued++
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(line int) {
+		logger.Warn("skipping overlong input line", "line", line, "max_length", options.MaxURLLength)
+	})
+	return queued, err
+}
+
+// Serve /scan at the -listen address, queueing the URLs of the POSTed body,
+// in the input format or the one of the format parameter, and answering
+// with their count once all are queued. The results go to the outputs.
+func startScanServer(ctx context.Context, options *Options, requests chan<- inspector.Request) (*http.Server, error) {
+	listener, err := net.Listen("tcp", options.Listen)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/scan", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "URLs to inspect are POSTed", http.StatusMethodNotAllowed)
+			return
+		}
+		format := options.InputFormat
+		if value := r.URL.Query().Get("format"); value != "" {
+			format = value
+		}
+		if err := validateInputFormat(format); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Stop queueing when the client gives up too.
+		queueCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(r.Context(), cancel)
+		defer stop()
+		queued, err := queueInput(queueCtx, options, format, r.Body, requests)
+		switch {
+		case ctx.Err() != nil:
+			http.Error(w, fmt.Sprintf("service stopped, %d URLs queued", queued), http.StatusServiceUnavailable)
+		case err != nil:
+			http.Error(w, fmt.Sprintf("%v, %d URLs queued", err, queued), http.StatusBadRequest)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]int{"queued": queued})
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("serving scans", "addr", options.Listen, "error", err)
+		}
+	}()
+	logger.Info("accepting URLs to inspect", "url", "http://"+listener.Addr().String()+"/scan")
+	return server, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Part of the input URLs inspected by one of several machines: the URLs
+// whose hash falls in the shard, so each URL lands in exactly one shard
+// whatever the order of the input.
+type shard struct {
+	index int // 1 to count.
+	count int // Zero when not sharding.
+}
+
+// Parse a shard given as index/count, such as 3/10.
+func parseShard(value string) (shard, error) {
+	if value == "" {
+		return shard{}, nil
+	}
+	index, count, ok := strings.Cut(value, "/")
+	s := shard{}
+	var indexErr, countErr error
+	s.index, indexErr = strconv.Atoi(strings.TrimSpace(index))
+	s.count, countErr = strconv.Atoi(strings.TrimSpace(count))
+	if !ok || indexErr != nil || countErr != nil || s.count < 1 || s.index < 1 || s.index > s.count {
+		return shard{}, fmt.Errorf("invalid shard %s, use index/count with index from 1 to count (e.g., 3/10)", value)
+	}
+	return s, nil
+}
+
+// Report whether a URL belongs to the shard.
+func (s shard) includes(url string) bool {
+	if s.count <= 1 {
+		return true
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(url))
+	return int(hash.Sum32()%uint32(s.count)) == s.index-1
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math/rand/v2"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Shuffles a stream of URLs through a buffer: once the buffer is full, each
+// new URL takes the place of a random buffered one, which is emitted. Lists
+// grouped by host are spread over the hosts without being read at once.
+type shuffler struct {
+	buffer []inspector.Request
+	size   int
+	emit   func(inspector.Request) bool
+}
+
+func newShuffler(size int, emit func(inspector.Request) bool) *shuffler {
+	return &shuffler{buffer: make([]inspector.Request, 0, min(size, 1024)), size: max(size, 1), emit: emit}
+}
+
+// Add a URL, emitting a random buffered one when full. Returns false once
+// emit does.
+func (s *shuffler) add(request inspector.Request) bool {
+	if len(s.buffer) < s.size {
+		s.buffer = append(s.buffer, request)
+		return true
+	}
+	i := rand.IntN(len(s.buffer))
+	emitted := s.buffer[i]
+	s.buffer[i] = request
+	return s.emit(emitted)
+}
+
+// Emit the buffered URLs in random order, at the end of the input.
+func (s *shuffler) flush() {
+	rand.Shuffle(len(s.buffer), func(i, j int) {
+		s.buffer[i], s.buffer[j] = s.buffer[j], s.buffer[i]
+	})
+	for _, request := range s.buffer {
+		if !s.emit(request) {
+			break
+		}
+	}
+	s.buffer = nil
+}
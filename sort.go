@@ -0,0 +1,53 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Fields the results can be sorted by.
+var sortFields = []string{"status", "length", "host", "suffix", "time"}
+
+func validateSort(field string) error {
+	if field != "" && !slices.Contains(sortFields, field) {
+		return fmt.Errorf("invalid sort field %s, use %s or %s", field, strings.Join(sortFields[:len(sortFields)-1], ", "), sortFields[len(sortFields)-1])
+	}
+	return nil
+}
+
+// Sort results by a field, status codes in ascending order, the largest and
+// slowest responses first, and hosts and suffixes alphabetically. Results
+// without a value for the field, failed URLs included, go last, and equal
+// results keep their order.
+func sortResults(results []*inspector.Result, field string) {
+	slices.SortStableFunc(results, func(a, b *inspector.Result) int {
+		if order := cmp.Compare(sortMissing(a, field), sortMissing(b, field)); order != 0 {
+			return order
+		}
+		switch field {
+		case "status":
+			return cmp.Compare(a.Data.StatusCode, b.Data.StatusCode)
+		case "length":
+			return cmp.Compare(b.Data.ContentLength, a.Data.ContentLength)
+		case "host":
+			return strings.Compare(a.Host, b.Host)
+		case "suffix":
+			return strings.Compare(a.Data.Suffix, b.Data.Suffix)
+		case "time":
+			return cmp.Compare(b.Data.ResponseTime, a.Data.ResponseTime)
+		}
+		return 0
+	})
+}
+
+// 1 for the results sorted last, without a value for the field.
+func sortMissing(result *inspector.Result, field string) int {
+	if result.Err != nil || (field == "suffix" && result.Data.Suffix == "") || (field == "status" && result.Data.StatusCode == 0) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rix4uni/linkinspector/pkg/inspector"
+)
+
+// Usage of the subcommands, scan being the default one.
+const subcommandsUsage = `Usage:
+  linkinspector [scan] [flags]                         inspect URLs (default)
+  linkinspector serve [-listen ADDR] [flags]           inspect the URLs POSTed to /scan or read from stdin
+  linkinspector mappings dump extensions|mime|severity print the built-in mappings
+  linkinspector mappings validate extensions|mime|severity FILE
+                                                       check a mapping file
+  linkinspector report [-o FILE] [-format html|md] [-sort FIELD] [FILE...]
+                                                       render saved JSON or JSONL results
+`
+
+// Dump the built-in extension or MIME type mappings, to be used as a base
+// for -extensions-file and -mime-file, or validate such a file.
+func runMappings(args []string) {
+	usage := func() {
+		fmt.Fprint(os.Stderr, subcommandsUsage)
+		os.Exit(1)
+	}
+	if len(args) < 2 || (args[1] != "extensions" && args[1] != "mime" && args[1] != "severity") {
+		usage()
+	}
+
+	switch {
+	case args[0] == "dump" && len(args) == 2:
+		extensions, mimeTypes := inspector.BuiltinMappings()
+		switch args[1] {
+		case "extensions":
+			os.Stdout.Write(extensions)
+		case "mime":
+			os.Stdout.Write(mimeTypes)
+		default:
+			os.Stdout.Write(inspector.BuiltinSeverities())
+		}
+	case args[0] == "validate" && len(args) == 3:
+		var entries int
+		var problems []string
+		var err error
+		if args[1] == "severity" {
+			entries, problems, err = inspector.ValidateSeverityFile(args[2])
+		} else {
+			entries, problems, err = inspector.ValidateMappingFile(args[2], args[1] == "mime")
+		}
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", args[2], problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d entries, valid\n", args[2], entries)
+	default:
+		usage()
+	}
+}
+
+// Render saved JSON or JSONL results, from files or stdin, into an HTML or
+// Markdown report.
+func runReport(args []string) {
+	flagSet := flag.NewFlagSet("report", flag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Fprint(os.Stderr, subcommandsUsage)
+		flagSet.PrintDefaults()
+	}
+	output := flagSet.String("o", "", "File to write the report to, stdout by default")
+	format := flagSet.String("format", "", "Report format, html or md, from the extension of the output file by default")
+	sortField := flagSet.String("sort", "", "Sort the results by status, length, host, suffix or time")
+	_ = flagSet.Parse(args)
+
+	if *format == "" {
+		*format = reportFormat(*output)
+	}
+	if *format != "html" && *format != "md" {
+		logger.Error("invalid report format, use html or md", "format", *format)
+		os.Exit(1)
+	}
+	if err := validateSort(*sortField); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	var input io.Reader = os.Stdin
+	if flagSet.NArg() > 0 {
+		var readers []io.Reader
+		for _, name := range flagSet.Args() {
+			file, err := openInput(context.Background(), name)
+			if err != nil {
+				logger.Error("opening input", "file", name, "error", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			readers = append(readers, file)
+		}
+		input = io.MultiReader(readers...)
+	}
+	data, err := loadReportData(input)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	if *sortField != "" {
+		sortResults(data.Results, *sortField)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			logger.Error("creating report", "file", *output, "error", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := renderReport(w, data, *format); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+}